@@ -0,0 +1,64 @@
+package feather
+
+// CreateAnonymousSession signs in a new anonymous user, eg a visitor who has not registered yet.
+// The session can later be upgraded with UpgradeAnonymousSession.
+func (c Client) CreateAnonymousSession() (*Session, error) {
+	credential, err := c.Credentials.Create(CredentialsCreateParams{
+		Type: CredentialTypeAnonymous,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if credential.Status != CredentialStatusValid || credential.Token == nil {
+		return nil, Error{
+			Type:    ErrorTypeAPI,
+			Code:    ErrorCodeCredentialStatusNotValid,
+			Message: "The anonymous credential is not valid",
+		}
+	}
+	return c.Sessions.Create(SessionsCreateParams{
+		CredentialToken: credential.Token,
+	})
+}
+
+// UpgradeAnonymousSession registers the anonymous session's user with an email address
+// and password. The user keeps their ID and metadata, and is no longer anonymous.
+func (c Client) UpgradeAnonymousSession(sessionID string, params AnonymousSessionUpgradeParams) (*Session, error) {
+	if params.Email == nil || params.Password == nil {
+		return nil, Error{
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeParameterMissing,
+			Message: "An email address and password must be provided to upgrade an anonymous session",
+		}
+	}
+	credential, err := c.Credentials.Create(CredentialsCreateParams{
+		Type:     CredentialTypeEmailPassword,
+		Email:    params.Email,
+		Password: params.Password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if credential.Status != CredentialStatusValid || credential.Token == nil {
+		return nil, Error{
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeCredentialStatusNotValid,
+			Message: "The email address and password could not be used to upgrade the session",
+		}
+	}
+	session, err := c.Sessions.Upgrade(sessionID, SessionsUpgradeParams{
+		CredentialToken: credential.Token,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The user is no longer anonymous, so any cached copy is out of date
+	c.userCache.invalidate(session.UserID)
+	return session, nil
+}
+
+// AnonymousSessionUpgradeParams ...
+type AnonymousSessionUpgradeParams struct {
+	Email    *string
+	Password *string
+}
@@ -0,0 +1,72 @@
+package feather
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Authenticator is the Feather authenticator object, representing a
+// WebAuthn authenticator (eg a passkey or security key) registered to a user.
+// https://feather.id/docs/reference/api#authenticatorObject
+type Authenticator struct {
+	ID         string     `json:"id"`
+	Object     string     `json:"object"`
+	UserID     string     `json:"user_id"`
+	Name       *string    `json:"name"`
+	AAGUID     string     `json:"aaguid"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// AuthenticatorList is a list of Feather authenticator objects.
+// https://feather.id/docs/reference/api#pagination
+type AuthenticatorList struct {
+	ListMeta
+	Data []*Authenticator `json:"data"`
+}
+
+// Authenticators provides an interface for accessing Feather API authenticator objects.
+// https://feather.id/docs/reference/api#authenticators
+type Authenticators interface {
+	List(params AuthenticatorsListParams) (*AuthenticatorList, error)
+	Revoke(id string) (*Authenticator, error)
+}
+
+type authenticators struct {
+	gateway gateway
+}
+
+func newAuthenticatorsResource(g gateway) authenticators {
+	return authenticators{
+		gateway: g,
+	}
+}
+
+// List a user's authenticators.
+// https://feather.id/docs/reference/api#listAuthenticators
+func (a authenticators) List(params AuthenticatorsListParams) (*AuthenticatorList, error) {
+	var authenticatorList AuthenticatorList
+	if err := a.gateway.sendRequest(http.MethodGet, pathAuthenticators, params, &authenticatorList); err != nil {
+		return nil, err
+	}
+	return &authenticatorList, nil
+}
+
+// AuthenticatorsListParams ...
+type AuthenticatorsListParams struct {
+	ListParams
+	UserID *string `json:"user_id"`
+}
+
+// Revoke an authenticator so it can no longer be used to sign in.
+// https://feather.id/docs/reference/api#revokeAuthenticator
+func (a authenticators) Revoke(id string) (*Authenticator, error) {
+	var authenticator Authenticator
+	path := strings.Join([]string{pathAuthenticators, id, "revoke"}, "/")
+	if err := a.gateway.sendRequest(http.MethodPost, path, nil, &authenticator); err != nil {
+		return nil, err
+	}
+	return &authenticator, nil
+}
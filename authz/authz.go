@@ -0,0 +1,202 @@
+// Package authz authorizes Feather sessions with roles and permissions.
+//
+// Each user has a set of roles, read from their metadata or from a custom RoleSource,
+// and each role grants a set of permissions such as "users:write". A permission of
+// "*" grants every permission, and a permission ending in ":*" (eg "users:*") grants
+// every permission with that prefix.
+package authz
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/feather-id/feather-go"
+)
+
+const (
+	// DefaultMetadataKey is the user metadata key MetadataRoleSource reads roles from.
+	DefaultMetadataKey = "roles"
+
+	// DefaultCacheTTL is how long an Authorizer caches the permissions of a session.
+	DefaultCacheTTL = time.Minute
+
+	// Expired sessions are removed from the cache once it holds this many sessions.
+	sweepCacheSize = 1024
+)
+
+// A RoleSource returns the roles of the session's user.
+type RoleSource interface {
+	Roles(ctx context.Context, session *feather.Session) ([]string, error)
+}
+
+// RoleSourceFunc adapts a function to a RoleSource.
+type RoleSourceFunc func(ctx context.Context, session *feather.Session) ([]string, error)
+
+// Roles calls f(ctx, session).
+func (f RoleSourceFunc) Roles(ctx context.Context, session *feather.Session) ([]string, error) {
+	return f(ctx, session)
+}
+
+// MetadataRoleSource reads a user's roles from a comma-separated list in their metadata,
+// eg {"roles": "admin,billing"}.
+type MetadataRoleSource struct {
+	Users feather.Users
+
+	// Key defaults to "roles".
+	Key string
+}
+
+// Roles retrieves the session's user and parses the roles in their metadata.
+func (s MetadataRoleSource) Roles(ctx context.Context, session *feather.Session) ([]string, error) {
+	user, err := s.Users.Retrieve(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	key := s.Key
+	if key == "" {
+		key = DefaultMetadataKey
+	}
+	var roles []string
+	for _, role := range strings.Split(user.Metadata[key], ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+// Authorizer decides whether sessions have permissions, caching each session's
+// permissions for CacheTTL.
+type Authorizer struct {
+	source      RoleSource
+	permissions map[string][]string
+	CacheTTL    time.Duration
+
+	// Sessions and Cookie are used by RequirePermission to validate the session cookie
+	// when the request context does not already hold a session (see feather.SessionFromContext).
+	Sessions feather.Sessions
+	Cookie   feather.SessionCookie
+
+	mu    sync.Mutex
+	cache map[string]cachedPermissions
+	now   func() time.Time
+}
+
+type cachedPermissions struct {
+	permissions map[string]bool
+	expiresAt   time.Time
+}
+
+// NewAuthorizer creates a new authorizer which reads roles from the source and
+// grants each role the permissions it maps to.
+func NewAuthorizer(source RoleSource, permissions map[string][]string) *Authorizer {
+	return &Authorizer{
+		source:      source,
+		permissions: permissions,
+		CacheTTL:    DefaultCacheTTL,
+		cache:       map[string]cachedPermissions{},
+		now:         time.Now,
+	}
+}
+
+// HasPermission reports whether the session's user has the permission.
+func (a *Authorizer) HasPermission(ctx context.Context, session *feather.Session, permission string) (bool, error) {
+	granted, err := a.sessionPermissions(ctx, session)
+	if err != nil {
+		return false, err
+	}
+	if granted["*"] || granted[permission] {
+		return true, nil
+	}
+	for i := strings.LastIndex(permission, ":"); i >= 0; i = strings.LastIndex(permission[:i], ":") {
+		if granted[permission[:i]+":*"] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Invalidate removes the session's cached permissions, eg after changing the user's roles.
+func (a *Authorizer) Invalidate(sessionID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.cache, sessionID)
+}
+
+// RequirePermission returns middleware which only calls the handler if the request's
+// session has the permission. It responds with 401 Unauthorized if the request has no
+// valid session, and 403 Forbidden if the session lacks the permission.
+func (a *Authorizer) RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, ok := feather.SessionFromContext(r.Context())
+			if !ok {
+				if a.Sessions == nil {
+					http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+					return
+				}
+				var err error
+				if session, err = a.Cookie.Validate(w, r, a.Sessions); err != nil {
+					http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+					return
+				}
+				r = r.WithContext(feather.ContextWithSession(r.Context(), session))
+			}
+			allowed, err := a.HasPermission(r.Context(), session, permission)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (a *Authorizer) sessionPermissions(ctx context.Context, session *feather.Session) (map[string]bool, error) {
+	a.mu.Lock()
+	cached, ok := a.cache[session.ID]
+	a.mu.Unlock()
+	if ok && a.now().Before(cached.expiresAt) {
+		return cached.permissions, nil
+	}
+
+	roles, err := a.source.Roles(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	granted := map[string]bool{}
+	for _, role := range roles {
+		for _, permission := range a.permissions[role] {
+			granted[permission] = true
+		}
+	}
+	if a.CacheTTL > 0 {
+		a.mu.Lock()
+		if len(a.cache) >= sweepCacheSize {
+			a.sweep()
+		}
+		a.cache[session.ID] = cachedPermissions{
+			permissions: granted,
+			expiresAt:   a.now().Add(a.CacheTTL),
+		}
+		a.mu.Unlock()
+	}
+	return granted, nil
+}
+
+// sweep removes expired sessions from the cache. The caller must hold a.mu.
+func (a *Authorizer) sweep() {
+	now := a.now()
+	for id, cached := range a.cache {
+		if !now.Before(cached.expiresAt) {
+			delete(a.cache, id)
+		}
+	}
+}
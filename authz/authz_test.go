@@ -0,0 +1,170 @@
+package authz_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/feather-id/feather-go"
+	"github.com/feather-id/feather-go/authz"
+	"github.com/stretchr/testify/assert"
+)
+
+var samplePermissions = map[string][]string{
+	"admin":   {"*"},
+	"support": {"users:read", "sessions:*"},
+	"billing": {"invoices:write"},
+}
+
+var sampleSession = &feather.Session{ID: "SES_foo", UserID: "USR_foo"}
+
+func staticRoles(roles ...string) (authz.RoleSource, *int) {
+	calls := 0
+	return authz.RoleSourceFunc(func(ctx context.Context, session *feather.Session) ([]string, error) {
+		calls++
+		return roles, nil
+	}), &calls
+}
+
+func TestHasPermission(t *testing.T) {
+	source, _ := staticRoles("support", "unknown")
+	a := authz.NewAuthorizer(source, samplePermissions)
+	tests := map[string]bool{
+		"users:read":             true,
+		"users:write":            false,
+		"sessions:revoke":        true,
+		"sessions:revoke:all":    true,
+		"invoices:write":         false,
+		"sessionsextra:read":     false,
+		"":                       false,
+		"users:read:unsupported": false,
+	}
+	for permission, want := range tests {
+		allowed, err := a.HasPermission(context.Background(), sampleSession, permission)
+		assert.Nil(t, err)
+		assert.Equal(t, want, allowed, permission)
+	}
+
+	admin, _ := staticRoles("admin")
+	allowed, err := authz.NewAuthorizer(admin, samplePermissions).HasPermission(context.Background(), sampleSession, "anything:at:all")
+	assert.Nil(t, err)
+	assert.True(t, allowed)
+}
+
+func TestHasPermission_Cache(t *testing.T) {
+	source, calls := staticRoles("billing")
+	a := authz.NewAuthorizer(source, samplePermissions)
+	for i := 0; i < 3; i++ {
+		a.HasPermission(context.Background(), sampleSession, "invoices:write")
+	}
+	assert.Equal(t, 1, *calls)
+
+	a.Invalidate(sampleSession.ID)
+	a.HasPermission(context.Background(), sampleSession, "invoices:write")
+	assert.Equal(t, 2, *calls)
+
+	a.HasPermission(context.Background(), &feather.Session{ID: "SES_bar"}, "invoices:write")
+	assert.Equal(t, 3, *calls)
+
+	a.CacheTTL = 10 * time.Millisecond
+	a.Invalidate(sampleSession.ID)
+	a.HasPermission(context.Background(), sampleSession, "invoices:write")
+	time.Sleep(20 * time.Millisecond)
+	a.HasPermission(context.Background(), sampleSession, "invoices:write")
+	assert.Equal(t, 5, *calls)
+}
+
+func TestHasPermission_SourceError(t *testing.T) {
+	a := authz.NewAuthorizer(authz.RoleSourceFunc(func(ctx context.Context, session *feather.Session) ([]string, error) {
+		return nil, errors.New("unavailable")
+	}), samplePermissions)
+	allowed, err := a.HasPermission(context.Background(), sampleSession, "users:read")
+	assert.False(t, allowed)
+	assert.Equal(t, "unavailable", err.Error())
+}
+
+func TestMetadataRoleSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodGet)
+		assert.Equal(t, "/v1/users/USR_foo", r.URL.Path)
+		w.WriteHeader(200)
+		json.NewEncoder(w).Encode(feather.User{
+			ID:       "USR_foo",
+			Metadata: map[string]string{"roles": "support, billing,", "groups": "admin"},
+		})
+	}))
+	defer server.Close()
+	client := createTestClient(server)
+
+	roles, err := authz.MetadataRoleSource{Users: client.Users}.Roles(context.Background(), sampleSession)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"support", "billing"}, roles)
+
+	roles, err = authz.MetadataRoleSource{Users: client.Users, Key: "groups"}.Roles(context.Background(), sampleSession)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"admin"}, roles)
+}
+
+func TestRequirePermission(t *testing.T) {
+	source, _ := staticRoles("support")
+	a := authz.NewAuthorizer(source, samplePermissions)
+	handler := func(permission string) http.Handler {
+		return a.RequirePermission(permission)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			session, ok := feather.SessionFromContext(r.Context())
+			assert.True(t, ok)
+			assert.Equal(t, "SES_foo", session.ID)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	}
+	signedIn := httptest.NewRequest(http.MethodPost, "/", nil)
+	signedIn = signedIn.WithContext(feather.ContextWithSession(signedIn.Context(), sampleSession))
+
+	w := httptest.NewRecorder()
+	handler("users:read").ServeHTTP(w, signedIn)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	handler("users:write").ServeHTTP(w, signedIn)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	handler("users:read").ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRequirePermission_SessionCookie(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+		w.Write([]byte(`{"object": "error", "type": "validation_error", "code": "session_token_invalid", "message": "An error message"}`))
+	}))
+	defer server.Close()
+	source, calls := staticRoles("admin")
+	a := authz.NewAuthorizer(source, samplePermissions)
+	a.Sessions = createTestClient(server).Sessions
+	handler := a.RequirePermission("users:read")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: "feather_session", Value: "malformed"})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, 0, *calls)
+}
+
+func createTestClient(server *httptest.Server) feather.Client {
+	comps := strings.SplitN(strings.TrimPrefix(server.URL, "http://"), ":", 2)
+	return feather.New("fooKey", &feather.Config{
+		Protocol:   feather.String("http"),
+		Host:       feather.String(comps[0]),
+		Port:       feather.String(comps[1]),
+		BasePath:   feather.String("/v1"),
+		HTTPClient: server.Client(),
+	})
+}
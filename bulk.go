@@ -0,0 +1,287 @@
+package feather
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBulkConcurrency = 4
+	defaultBulkMaxRetries  = 5
+	defaultBulkBackoff     = time.Second
+)
+
+// BulkResultStatus represents the outcome of a single item in a bulk operation.
+type BulkResultStatus string
+
+const (
+	// The operation succeeded.
+	BulkResultStatusSucceeded = "succeeded"
+
+	// The operation failed; see the result's error.
+	BulkResultStatusFailed = "failed"
+
+	// The item was already completed according to the checkpoint and was skipped.
+	BulkResultStatusSkipped = "skipped"
+
+	// The executor is in dry-run mode and no request was sent.
+	BulkResultStatusDryRun = "dry_run"
+)
+
+// BulkUserUpdate is a single user update to be executed in bulk.
+type BulkUserUpdate struct {
+	UserID string
+	Params UsersUpdateParams
+
+	// Key identifies the update in the checkpoint. It defaults to the update's
+	// position in the input, so resumed runs must receive the same items in the same order.
+	Key string
+}
+
+// BulkUserResult is the result of a single user update executed in bulk.
+// Err is set if the operation failed, or if it succeeded but could not be
+// recorded in the checkpoint.
+type BulkUserResult struct {
+	Index  int
+	UserID string
+	Status BulkResultStatus
+	User   *User
+	Err    error
+}
+
+// BulkReport is the per-item report of a bulk operation, ordered by the items' input order.
+type BulkReport struct {
+	Results   []BulkUserResult
+	Succeeded int
+	Failed    int
+	Skipped   int
+	DryRun    int
+}
+
+// BulkCheckpoint records which items have been processed, by key, so an interrupted
+// bulk operation can be resumed without repeating completed items.
+type BulkCheckpoint interface {
+	Done(key string) bool
+	MarkDone(key string) error
+}
+
+// BulkUsers executes user operations in bulk with bounded concurrency.
+// Requests rejected with a rate limit error are retried with exponential backoff.
+type BulkUsers struct {
+	Users Users
+
+	// Concurrency is the maximum number of requests in flight. Defaults to 4.
+	Concurrency int
+
+	// RequestsPerSecond limits the request rate across all workers. Zero means no limit.
+	RequestsPerSecond float64
+
+	// MaxRetries is the number of times a rate limited request is retried. Defaults to 5;
+	// set it to Int(0) to disable retries.
+	MaxRetries *int
+
+	// Backoff is the delay before the first retry, doubling on each subsequent retry. Defaults to 1s.
+	Backoff time.Duration
+
+	// DryRun reports the operations which would be executed without sending any requests.
+	DryRun bool
+
+	// Checkpoint is optional. Completed items are recorded and skipped on later runs.
+	Checkpoint BulkCheckpoint
+}
+
+// Update executes each user update received from items until the channel is closed.
+func (b BulkUsers) Update(items <-chan BulkUserUpdate) *BulkReport {
+	return b.run(items, func(item BulkUserUpdate) (*User, error) {
+		return b.Users.Update(item.UserID, item.Params)
+	})
+}
+
+// UpdateIDs applies the same update to each user ID received from ids until the channel is closed.
+// Items are recorded in the checkpoint by user ID.
+func (b BulkUsers) UpdateIDs(ids <-chan string, params UsersUpdateParams) *BulkReport {
+	items := make(chan BulkUserUpdate)
+	go func() {
+		defer close(items)
+		for id := range ids {
+			items <- BulkUserUpdate{UserID: id, Params: params, Key: id}
+		}
+	}()
+	return b.Update(items)
+}
+
+func (b BulkUsers) run(items <-chan BulkUserUpdate, op func(item BulkUserUpdate) (*User, error)) *BulkReport {
+	concurrency := b.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	// Share a ticker across workers to limit the overall request rate
+	var throttle <-chan time.Time
+	if b.RequestsPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / b.RequestsPerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	type indexedItem struct {
+		index int
+		item  BulkUserUpdate
+	}
+	queue := make(chan indexedItem)
+	go func() {
+		defer close(queue)
+		i := 0
+		for item := range items {
+			queue <- indexedItem{index: i, item: item}
+			i++
+		}
+	}()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	report := BulkReport{}
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q := range queue {
+				result := b.execute(q.index, q.item, throttle, op)
+				mu.Lock()
+				report.Results = append(report.Results, result)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(report.Results, func(i, j int) bool {
+		return report.Results[i].Index < report.Results[j].Index
+	})
+	for _, result := range report.Results {
+		switch result.Status {
+		case BulkResultStatusSucceeded:
+			report.Succeeded++
+		case BulkResultStatusFailed:
+			report.Failed++
+		case BulkResultStatusSkipped:
+			report.Skipped++
+		case BulkResultStatusDryRun:
+			report.DryRun++
+		}
+	}
+	return &report
+}
+
+func (b BulkUsers) execute(index int, item BulkUserUpdate, throttle <-chan time.Time, op func(item BulkUserUpdate) (*User, error)) BulkUserResult {
+	result := BulkUserResult{
+		Index:  index,
+		UserID: item.UserID,
+	}
+	key := item.Key
+	if key == "" {
+		key = strconv.Itoa(index)
+	}
+	if b.Checkpoint != nil && b.Checkpoint.Done(key) {
+		result.Status = BulkResultStatusSkipped
+		return result
+	}
+	if b.DryRun {
+		result.Status = BulkResultStatusDryRun
+		return result
+	}
+
+	maxRetries := defaultBulkMaxRetries
+	if b.MaxRetries != nil {
+		maxRetries = *b.MaxRetries
+	}
+	backoff := b.Backoff
+	if backoff <= 0 {
+		backoff = defaultBulkBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		if throttle != nil {
+			<-throttle
+		}
+		result.User, result.Err = op(item)
+		ferr, ok := result.Err.(Error)
+		if !ok || ferr.Type != ErrorTypeRateLimit || attempt >= maxRetries {
+			break
+		}
+		time.Sleep(backoff << uint(attempt))
+	}
+	if result.Err != nil {
+		result.Status = BulkResultStatusFailed
+		return result
+	}
+	result.Status = BulkResultStatusSucceeded
+	if b.Checkpoint != nil {
+		if err := b.Checkpoint.MarkDone(key); err != nil {
+			result.Err = err
+		}
+	}
+	return result
+}
+
+// FileCheckpoint is a BulkCheckpoint which appends completed keys to a file,
+// one per line, so a bulk operation can be resumed after the process exits.
+type FileCheckpoint struct {
+	mu   sync.Mutex
+	file *os.File
+	done map[string]bool
+}
+
+// NewFileCheckpoint opens (or creates) the checkpoint file at path and loads
+// the keys completed by previous runs.
+func NewFileCheckpoint(path string) (*FileCheckpoint, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	done := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if key := strings.TrimSpace(scanner.Text()); key != "" {
+			done[key] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &FileCheckpoint{
+		file: file,
+		done: done,
+	}, nil
+}
+
+// Done reports whether the key was completed by this or a previous run.
+func (c *FileCheckpoint) Done(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done[key]
+}
+
+// MarkDone records the key as completed.
+func (c *FileCheckpoint) MarkDone(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.done[key] {
+		return nil
+	}
+	if _, err := c.file.WriteString(key + "\n"); err != nil {
+		return err
+	}
+	c.done[key] = true
+	return nil
+}
+
+// Close closes the checkpoint file.
+func (c *FileCheckpoint) Close() error {
+	return c.file.Close()
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/feather-id/feather-go"
+)
+
+const (
+	envAPIKey = "FEATHER_API_KEY"
+	envConfig = "FEATHER_CONFIG"
+)
+
+// config is the contents of the config file.
+// Only the API key is required; the remaining fields are useful for
+// pointing the tool at a development environment.
+type config struct {
+	APIKey   string `json:"api_key"`
+	Protocol string `json:"protocol"`
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	BasePath string `json:"base_path"`
+}
+
+func loadConfig(path string, getenv func(string) string) (config, error) {
+	var cfg config
+	explicit := path != ""
+	if !explicit {
+		path = getenv(envConfig)
+		explicit = path != ""
+	}
+	if !explicit {
+		if home := getenv("HOME"); home != "" {
+			path = filepath.Join(home, ".feather", "config.json")
+		}
+	}
+	if path != "" {
+		bytes, err := ioutil.ReadFile(path)
+		if err != nil && (explicit || !os.IsNotExist(err)) {
+			return cfg, fmt.Errorf("failed to read config file: %v", err)
+		}
+		if err == nil {
+			if err := json.Unmarshal(bytes, &cfg); err != nil {
+				return cfg, fmt.Errorf("failed to parse config file %v: %v", path, err)
+			}
+		}
+	}
+	if apiKey := getenv(envAPIKey); apiKey != "" {
+		cfg.APIKey = apiKey
+	}
+	return cfg, nil
+}
+
+// client creates a Feather client from the loaded config.
+func (e *env) client() (feather.Client, error) {
+	if e.config.APIKey == "" {
+		return feather.Client{}, errors.New("no API key provided; set " + envAPIKey + " or add api_key to the config file")
+	}
+	optional := func(v string) *string {
+		if v == "" {
+			return nil
+		}
+		return feather.String(v)
+	}
+	return feather.New(e.config.APIKey, &feather.Config{
+		Protocol: optional(e.config.Protocol),
+		Host:     optional(e.config.Host),
+		Port:     optional(e.config.Port),
+		BasePath: optional(e.config.BasePath),
+	}), nil
+}
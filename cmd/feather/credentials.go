@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/feather-id/feather-go"
+)
+
+const envPassword = "FEATHER_PASSWORD"
+
+func credentialsCreate(e *env, args []string) error {
+	fs := e.newFlagSet("credentials create")
+	credentialType := fs.String("type", "", "credential type (eg email, email|password, username|password, phone)")
+	email := fs.String("email", "", "email address")
+	username := fs.String("username", "", "username")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin (default $"+envPassword+")")
+	phone := fs.String("phone", "", "phone number (E.164)")
+	templateName := fs.String("template-name", "", "name of the email template to send")
+	if err := e.parseFlags(fs, args, 0, "[flags]"); err != nil {
+		return err
+	}
+	if *credentialType == "" {
+		return fmt.Errorf("the -type flag is required")
+	}
+	password, err := e.password(*passwordStdin)
+	if err != nil {
+		return err
+	}
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	credential, err := client.Credentials.Create(feather.CredentialsCreateParams{
+		Type:         feather.CredentialType(*credentialType),
+		Email:        optionalString(fs, "email", *email),
+		Username:     optionalString(fs, "username", *username),
+		Password:     password,
+		Phone:        optionalString(fs, "phone", *phone),
+		TemplateName: optionalString(fs, "template-name", *templateName),
+	})
+	if err != nil {
+		return err
+	}
+	return e.print(credential, []string{"ID", "TYPE", "STATUS", "TOKEN", "EXPIRES"}, [][]string{{
+		credential.ID,
+		string(credential.Type),
+		string(credential.Status),
+		formatString(credential.Token),
+		formatTime(&credential.ExpiresAt),
+	}})
+}
+
+// password reads the password from the first line of stdin, or otherwise from
+// the FEATHER_PASSWORD environment variable. It returns nil if neither is set.
+func (e *env) password(fromStdin bool) (*string, error) {
+	if !fromStdin {
+		if password := e.getenv(envPassword); password != "" {
+			return &password, nil
+		}
+		return nil, nil
+	}
+	line, err := bufio.NewReader(e.stdin).ReadString('\n')
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the password from stdin: %v", err)
+		}
+		return nil, fmt.Errorf("the password read from stdin is empty")
+	}
+	return &password, nil
+}
@@ -0,0 +1,199 @@
+// Command feather is a command-line tool for administering a Feather project.
+//
+// Usage:
+//
+//	feather [global flags] <resource> <action> [flags] [args]
+//
+// Resources and actions:
+//
+//	users list|get|update
+//	sessions list|revoke|validate
+//	credentials create
+//	token decode
+//
+// The API key is read from the -api-key flag, the FEATHER_API_KEY environment
+// variable or the config file (default $HOME/.feather/config.json), in that order.
+//
+// Passwords are never passed as flags, which would expose them in the process list
+// and shell history. They are read from stdin with -password-stdin or from the
+// FEATHER_PASSWORD environment variable.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+	output string
+	config config
+}
+
+type action func(e *env, args []string) error
+
+var resources = map[string]map[string]action{
+	"users": {
+		"list":   usersList,
+		"get":    usersGet,
+		"update": usersUpdate,
+	},
+	"sessions": {
+		"list":     sessionsList,
+		"revoke":   sessionsRevoke,
+		"validate": sessionsValidate,
+	},
+	"credentials": {
+		"create": credentialsCreate,
+	},
+	"token": {
+		"decode": tokenDecode,
+	},
+}
+
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer, getenv func(string) string) int {
+	e := &env{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		getenv: getenv,
+	}
+	fs := flag.NewFlagSet("feather", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	apiKey := fs.String("api-key", "", "Feather API key (overrides FEATHER_API_KEY)")
+	configPath := fs.String("config", "", "path to the config file (default $HOME/.feather/config.json)")
+	fs.StringVar(&e.output, "o", outputTable, "output format: table or json")
+	fs.Usage = func() { printUsage(stderr, fs) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if e.output != outputTable && e.output != outputJSON {
+		fmt.Fprintf(stderr, "feather: unknown output format %q\n", e.output)
+		return 2
+	}
+
+	args = fs.Args()
+	if len(args) < 2 {
+		fs.Usage()
+		return 2
+	}
+	actions, ok := resources[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "feather: unknown resource %q\n", args[0])
+		return 2
+	}
+	act, ok := actions[args[1]]
+	if !ok {
+		fmt.Fprintf(stderr, "feather: unknown action %q for %v\n", args[1], args[0])
+		return 2
+	}
+
+	cfg, err := loadConfig(*configPath, getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "feather: %v\n", err)
+		return 1
+	}
+	if *apiKey != "" {
+		cfg.APIKey = *apiKey
+	}
+	e.config = cfg
+
+	if err := act(e, args[2:]); err != nil {
+		if err != errUsage {
+			fmt.Fprintf(stderr, "feather: %v\n", err)
+		}
+		return 1
+	}
+	return 0
+}
+
+func printUsage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "Usage: feather [global flags] <resource> <action> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Resources and actions:")
+	var names []string
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var acts []string
+		for act := range resources[name] {
+			acts = append(acts, act)
+		}
+		sort.Strings(acts)
+		fmt.Fprintf(w, "  %-12v %v\n", name, strings.Join(acts, "|"))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Global flags:")
+	fs.PrintDefaults()
+}
+
+// newFlagSet creates the flag set for an action, writing errors to stderr.
+func (e *env) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	return fs
+}
+
+// parseFlags parses the action flags and checks the number of positional arguments.
+func (e *env) parseFlags(fs *flag.FlagSet, args []string, nArgs int, argsUsage string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != nArgs {
+		fmt.Fprintf(e.stderr, "Usage: feather %v %v\n", fs.Name(), argsUsage)
+		fs.PrintDefaults()
+		return errUsage
+	}
+	return nil
+}
+
+// optionalString returns nil if the flag was not set.
+func optionalString(fs *flag.FlagSet, name string, v string) *string {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	if !set {
+		return nil
+	}
+	return &v
+}
+
+// keyValueFlag collects repeated key=value flags.
+type keyValueFlag map[string]string
+
+func (f keyValueFlag) String() string {
+	var pairs []string
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (f keyValueFlag) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return fmt.Errorf("expected key=value but got %q", s)
+	}
+	f[kv[0]] = kv[1]
+	return nil
+}
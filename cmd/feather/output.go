@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// print writes v as indented JSON, or as a table with the provided header and rows.
+func (e *env) print(v interface{}, header []string, rows [][]string) error {
+	if e.output == outputJSON {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func formatString(v *string) string {
+	if v == nil {
+		return "-"
+	}
+	return *v
+}
+
+func formatTime(v *time.Time) string {
+	if v == nil || v.IsZero() {
+		return "-"
+	}
+	return v.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/feather-id/feather-go"
+)
+
+var sessionHeader = []string{"ID", "STATUS", "USER ID", "CREATED", "REVOKED"}
+
+func sessionRow(s *feather.Session) []string {
+	return []string{
+		s.ID,
+		string(s.Status),
+		s.UserID,
+		formatTime(&s.CreatedAt),
+		formatTime(s.RevokedAt),
+	}
+}
+
+func printSession(e *env, s *feather.Session) error {
+	return e.print(s, sessionHeader, [][]string{sessionRow(s)})
+}
+
+func sessionsList(e *env, args []string) error {
+	fs := e.newFlagSet("sessions list")
+	userID := fs.String("user-id", "", "only list sessions belonging to this user")
+	status := fs.String("status", "", "only list sessions with these comma-separated statuses")
+	limit := fs.Uint("limit", 0, "maximum number of sessions to return")
+	startingAfter := fs.String("starting-after", "", "cursor for the next page")
+	endingBefore := fs.String("ending-before", "", "cursor for the previous page")
+	if err := e.parseFlags(fs, args, 0, "[flags]"); err != nil {
+		return err
+	}
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	params := feather.SessionsListParams{
+		UserID: optionalString(fs, "user-id", *userID),
+	}
+	if *status != "" {
+		for _, s := range strings.Split(*status, ",") {
+			params.Status = append(params.Status, feather.SessionStatus(s))
+		}
+	}
+	if *limit > 0 {
+		params.Limit = feather.UInt32(uint32(*limit))
+	}
+	params.StartingAfter = optionalString(fs, "starting-after", *startingAfter)
+	params.EndingBefore = optionalString(fs, "ending-before", *endingBefore)
+	sessionList, err := client.Sessions.List(params)
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, s := range sessionList.Data {
+		rows = append(rows, sessionRow(s))
+	}
+	return e.print(sessionList, sessionHeader, rows)
+}
+
+func sessionsRevoke(e *env, args []string) error {
+	fs := e.newFlagSet("sessions revoke")
+	sessionToken := fs.String("session-token", "", "session token to revoke alongside the session")
+	if err := e.parseFlags(fs, args, 1, "[flags] <session id>"); err != nil {
+		return err
+	}
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	session, err := client.Sessions.Revoke(fs.Arg(0), feather.SessionsRevokeParams{
+		SessionToken: optionalString(fs, "session-token", *sessionToken),
+	})
+	if err != nil {
+		return err
+	}
+	return printSession(e, session)
+}
+
+func sessionsValidate(e *env, args []string) error {
+	fs := e.newFlagSet("sessions validate")
+	if err := e.parseFlags(fs, args, 1, "<session token>"); err != nil {
+		return err
+	}
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	session, err := client.Sessions.Validate(feather.SessionsValidateParams{
+		SessionToken: feather.String(fs.Arg(0)),
+	})
+	if err != nil {
+		return err
+	}
+	return printSession(e, session)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/feather-id/feather-go"
+)
+
+func tokenDecode(e *env, args []string) error {
+	fs := e.newFlagSet("token decode")
+	if err := e.parseFlags(fs, args, 1, "<session token>"); err != nil {
+		return err
+	}
+	decoded := feather.DecodeSessionToken(fs.Arg(0))
+	rows := [][]string{
+		{"status", string(decoded.Status)},
+		{"explanation", decoded.Explanation},
+	}
+	for _, section := range []struct {
+		name   string
+		values map[string]interface{}
+	}{{"header", decoded.Header}, {"claims", decoded.Claims}} {
+		var keys []string
+		for k := range section.values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			rows = append(rows, []string{section.name + "." + k, formatClaim(k, section.values[k])})
+		}
+	}
+	return e.print(decoded, []string{"FIELD", "VALUE"}, rows)
+}
+
+// formatClaim renders timestamp claims as RFC 3339 times.
+func formatClaim(key string, v interface{}) string {
+	switch key {
+	case "exp", "iat", "nbf", "cat":
+		if f, ok := v.(float64); ok {
+			return time.Unix(int64(f), 0).UTC().Format(time.RFC3339)
+		}
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	bytes, _ := json.Marshal(v)
+	return string(bytes)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/feather-id/feather-go"
+)
+
+var userHeader = []string{"ID", "EMAIL", "USERNAME", "PHONE", "ANONYMOUS", "EMAIL VERIFIED", "CREATED"}
+
+func userRow(u *feather.User) []string {
+	return []string{
+		u.ID,
+		formatString(u.Email),
+		formatString(u.Username),
+		formatString(u.Phone),
+		fmt.Sprintf("%v", u.IsAnonymous),
+		fmt.Sprintf("%v", u.IsEmailVerified),
+		formatTime(&u.CreatedAt),
+	}
+}
+
+func printUser(e *env, u *feather.User) error {
+	if e.output == outputJSON {
+		return e.print(u, nil, nil)
+	}
+	rows := [][]string{
+		{"ID", u.ID},
+		{"Email", formatString(u.Email)},
+		{"Username", formatString(u.Username)},
+		{"Phone", formatString(u.Phone)},
+		{"Anonymous", fmt.Sprintf("%v", u.IsAnonymous)},
+		{"Email verified", fmt.Sprintf("%v", u.IsEmailVerified)},
+		{"Created", formatTime(&u.CreatedAt)},
+		{"Updated", formatTime(&u.UpdatedAt)},
+		{"First active", formatTime(u.FirstActiveAt)},
+		{"Last active", formatTime(u.LastActiveAt)},
+	}
+	var keys []string
+	for k := range u.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rows = append(rows, []string{"metadata." + k, u.Metadata[k]})
+	}
+	return e.print(u, []string{"FIELD", "VALUE"}, rows)
+}
+
+func usersList(e *env, args []string) error {
+	fs := e.newFlagSet("users list")
+	limit := fs.Uint("limit", 0, "maximum number of users to return")
+	startingAfter := fs.String("starting-after", "", "cursor for the next page")
+	endingBefore := fs.String("ending-before", "", "cursor for the previous page")
+	if err := e.parseFlags(fs, args, 0, "[flags]"); err != nil {
+		return err
+	}
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	params := feather.UsersListParams{}
+	if *limit > 0 {
+		params.Limit = feather.UInt32(uint32(*limit))
+	}
+	params.StartingAfter = optionalString(fs, "starting-after", *startingAfter)
+	params.EndingBefore = optionalString(fs, "ending-before", *endingBefore)
+	userList, err := client.Users.List(params)
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, u := range userList.Data {
+		rows = append(rows, userRow(u))
+	}
+	return e.print(userList, userHeader, rows)
+}
+
+func usersGet(e *env, args []string) error {
+	fs := e.newFlagSet("users get")
+	if err := e.parseFlags(fs, args, 1, "<user id>"); err != nil {
+		return err
+	}
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	user, err := client.Users.Retrieve(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return printUser(e, user)
+}
+
+func usersUpdate(e *env, args []string) error {
+	fs := e.newFlagSet("users update")
+	email := fs.String("email", "", "new email address")
+	username := fs.String("username", "", "new username")
+	phone := fs.String("phone", "", "new phone number (E.164)")
+	metadata := keyValueFlag{}
+	fs.Var(metadata, "metadata", "metadata `key=value` to set (repeatable)")
+	if err := e.parseFlags(fs, args, 1, "[flags] <user id>"); err != nil {
+		return err
+	}
+	params := feather.UsersUpdateParams{
+		Email:    optionalString(fs, "email", *email),
+		Username: optionalString(fs, "username", *username),
+		Phone:    optionalString(fs, "phone", *phone),
+	}
+	if len(metadata) > 0 {
+		m := map[string]string(metadata)
+		params.Metadata = &m
+	}
+	if params.Email == nil && params.Username == nil && params.Phone == nil && params.Metadata == nil {
+		return fmt.Errorf("nothing to update; provide at least one of %v", strings.Join([]string{"-email", "-username", "-phone", "-metadata"}, ", "))
+	}
+	client, err := e.client()
+	if err != nil {
+		return err
+	}
+	user, err := client.Users.Update(fs.Arg(0), params)
+	if err != nil {
+		return err
+	}
+	return printUser(e, user)
+}
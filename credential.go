@@ -2,6 +2,7 @@ package feather
 
 import (
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -33,8 +34,45 @@ const (
 
 	// A username and password were provided.
 	CredentialTypeUsernamePassword = "username|password"
+
+	// Only a phone number was provided.
+	CredentialTypePhone = "phone"
+
+	// An OpenID Connect identity token issued by an identity provider was provided.
+	CredentialTypeOIDCIDToken = "oidc|id_token"
+
+	// An OAuth authorization code issued by an identity provider was provided.
+	CredentialTypeOAuthCode = "oauth|code"
+
+	// A code generated by a user's verified TOTP factor was provided.
+	// The resulting credential is used to upgrade an existing session.
+	CredentialTypeTOTP = "totp"
+
+	// A WebAuthn attestation or assertion response was provided.
+	CredentialTypeWebAuthn = "webauthn"
+
+	// No authentication information was provided. The resulting credential
+	// creates a session for a new anonymous user.
+	CredentialTypeAnonymous = "anonymous"
+)
+
+// IdentityProvider represents an external identity provider.
+type IdentityProvider string
+
+const (
+	// Sign in with Apple.
+	IdentityProviderApple = "apple"
+
+	// Sign in with GitHub.
+	IdentityProviderGitHub = "github"
+
+	// Sign in with Google.
+	IdentityProviderGoogle = "google"
 )
 
+// e164Regexp matches phone numbers in the E.164 format (eg +14155552671).
+var e164Regexp = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+
 // Credential is a Feather credential object.
 // https://feather.id/docs/reference/api#credentialObject
 type Credential struct {
@@ -52,6 +90,10 @@ type Credential struct {
 type Credentials interface {
 	Create(params CredentialsCreateParams) (*Credential, error)
 	Update(id string, params CredentialsUpdateParams) (*Credential, error)
+	BeginWebAuthnRegistration(params CredentialsBeginWebAuthnRegistrationParams) (*WebAuthnChallenge, error)
+	FinishWebAuthnRegistration(challengeID string, params CredentialsFinishWebAuthnParams) (*Credential, error)
+	BeginWebAuthnAssertion(params CredentialsBeginWebAuthnAssertionParams) (*WebAuthnChallenge, error)
+	FinishWebAuthnAssertion(challengeID string, params CredentialsFinishWebAuthnParams) (*Credential, error)
 }
 
 type credentials struct {
@@ -67,6 +109,15 @@ func newCredentialsResource(g gateway) credentials {
 // Create a new credential.
 // https://feather.id/docs/reference/api#createCredential
 func (c credentials) Create(params CredentialsCreateParams) (*Credential, error) {
+	if params.Type == CredentialTypePhone {
+		if params.Phone == nil || !e164Regexp.MatchString(*params.Phone) {
+			return nil, Error{
+				Type:    ErrorTypeValidation,
+				Code:    ErrorCodeParameterInvalid,
+				Message: "The phone number must be provided in the E.164 format (eg +14155552671)",
+			}
+		}
+	}
 	var credential Credential
 	if err := c.gateway.sendRequest(http.MethodPost, pathCredentials, params, &credential); err != nil {
 		return nil, err
@@ -76,11 +127,19 @@ func (c credentials) Create(params CredentialsCreateParams) (*Credential, error)
 
 // CredentialsCreateParams ...
 type CredentialsCreateParams struct {
-	Type         CredentialType `json:"type"`
-	Email        *string        `json:"email"`
-	Username     *string        `json:"username"`
-	Password     *string        `json:"password"`
-	TemplateName *string        `json:"template_name"`
+	Type             CredentialType `json:"type"`
+	Email            *string        `json:"email"`
+	Phone            *string        `json:"phone"`
+	Username         *string        `json:"username"`
+	Password         *string        `json:"password"`
+	TemplateName     *string        `json:"template_name"`
+	Provider         *string        `json:"provider"`
+	IDToken          *string        `json:"id_token"`
+	Code             *string        `json:"code"`
+	RedirectURI      *string        `json:"redirect_uri"`
+	CodeVerifier     *string        `json:"code_verifier"`
+	FactorID         *string        `json:"factor_id"`
+	VerificationCode *string        `json:"verification_code"`
 }
 
 // Update a credential.
@@ -0,0 +1,140 @@
+package feather
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+)
+
+const (
+	defaultCSRFHeaderName = "X-CSRF-Token"
+	defaultCSRFFormField  = "csrf_token"
+)
+
+type csrfTokenContextKey struct{}
+
+// CSRF is middleware which protects cookie-authenticated handlers from cross-site
+// request forgery.
+//
+// The middleware validates the session held in the session cookie and derives a
+// CSRF token from the session ID. The session and token are added to the request
+// context (see SessionFromContext and CSRFTokenFromContext) so templates can embed
+// the token in forms. Requests with unsafe methods (ie not GET, HEAD, OPTIONS or
+// TRACE) with a session cookie must send the token in the header or form field,
+// otherwise they are rejected with 403 Forbidden. Use NewCSRF to create the middleware.
+type CSRF struct {
+	Client Client
+
+	// secret is the key used to derive CSRF tokens. It must be kept private.
+	secret []byte
+
+	// Cookie configures the session cookie the session is read from.
+	Cookie SessionCookie
+
+	// HeaderName and FormField name where the token is sent. They default
+	// to "X-CSRF-Token" and "csrf_token".
+	HeaderName string
+	FormField  string
+
+	// FailureHandler handles rejected requests. It defaults to responding with 403 Forbidden.
+	FailureHandler http.Handler
+}
+
+// NewCSRF creates a new CSRF middleware which derives tokens with the secret.
+// The secret must be kept private.
+func NewCSRF(client Client, secret []byte) (*CSRF, error) {
+	if len(secret) == 0 {
+		return nil, Error{
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeParameterMissing,
+			Message: "The CSRF secret must not be empty",
+		}
+	}
+	return &CSRF{
+		Client: client,
+		secret: secret,
+	}, nil
+}
+
+// Token returns the CSRF token for the session. It is empty if the session is nil
+// or the CSRF middleware was not created with NewCSRF.
+func (c CSRF) Token(session *Session) string {
+	if session == nil || len(c.secret) == 0 {
+		return ""
+	}
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(session.ID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Middleware wraps the handler with CSRF protection.
+// Requests without a session cookie are passed through without a session in their context.
+// If the session cookie cannot be validated (eg the session was revoked or the Feather API
+// is unreachable), requests with safe methods are passed through without a session and
+// requests with unsafe methods are rejected.
+func (c CSRF) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := c.Cookie.Token(r); !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		// Without a secret no token can be verified, so the session is not established
+		if len(c.secret) == 0 {
+			if !isSafeMethod(r.Method) {
+				c.fail(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		session, err := c.Cookie.Validate(w, r, c.Client.Sessions)
+		if err != nil {
+			if !isSafeMethod(r.Method) {
+				c.fail(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		token := c.Token(session)
+		if !isSafeMethod(r.Method) && !hmac.Equal([]byte(token), []byte(c.requestToken(r))) {
+			c.fail(w, r)
+			return
+		}
+		ctx := ContextWithSession(r.Context(), session)
+		ctx = context.WithValue(ctx, csrfTokenContextKey{}, token)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// CSRFTokenFromContext returns the CSRF token added to the request context by CSRF.Middleware.
+// It is empty if the request has no valid session.
+func CSRFTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(csrfTokenContextKey{}).(string)
+	return token
+}
+
+func (c CSRF) requestToken(r *http.Request) string {
+	if token := r.Header.Get(stringOrDefault(c.HeaderName, defaultCSRFHeaderName)); token != "" {
+		return token
+	}
+	return r.PostFormValue(stringOrDefault(c.FormField, defaultCSRFFormField))
+}
+
+func (c CSRF) fail(w http.ResponseWriter, r *http.Request) {
+	if c.FailureHandler != nil {
+		c.FailureHandler.ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, "Invalid CSRF token", http.StatusForbidden)
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -37,8 +37,11 @@ const (
 	ErrorCodeCredentialStatusImmutable     ErrorCode = "credential_status_immutable"
 	ErrorCodeCredentialTokenInvalid        ErrorCode = "credential_token_invalid"
 	ErrorCodeCredentialTokenExpired        ErrorCode = "credential_token_expired"
+	ErrorCodeFactorAlreadyVerified         ErrorCode = "factor_already_verified"
+	ErrorCodeFactorNotVerified             ErrorCode = "factor_not_verified"
 	ErrorCodeHeaderEmpty                   ErrorCode = "header_empty"
 	ErrorCodeHeaderMissing                 ErrorCode = "header_missing"
+	ErrorCodeMetadataConflict              ErrorCode = "metadata_conflict"
 	ErrorCodeNotFound                      ErrorCode = "not_found"
 	ErrorCodeOneTimeCodeInvalid            ErrorCode = "one_time_code_invalid"
 	ErrorCodeOneTimeCodeUsed               ErrorCode = "one_time_code_used"
@@ -49,6 +52,7 @@ const (
 	ErrorCodeParameterUnknown              ErrorCode = "parameter_unknown"
 	ErrorCodeParametersExclusive           ErrorCode = "parameters_exclusive"
 	ErrorCodePasswordInvalid               ErrorCode = "password_invalid"
+	ErrorCodePreconditionFailed            ErrorCode = "precondition_failed"
 	ErrorCodePublicKeyNotFound             ErrorCode = "public_key_not_found"
 	ErrorCodeSessionExpired                ErrorCode = "session_expired"
 	ErrorCodeSessionInactive               ErrorCode = "session_inactive"
@@ -56,6 +60,8 @@ const (
 	ErrorCodeSessionTokenInvalid           ErrorCode = "session_token_invalid"
 	ErrorCodeSessionTokenExpired           ErrorCode = "session_token_expired"
 	ErrorCodeUserBlocked                   ErrorCode = "user_blocked"
+	ErrorCodeWebhookSignatureInvalid       ErrorCode = "webhook_signature_invalid"
+	ErrorCodeWebhookTimestampExpired       ErrorCode = "webhook_timestamp_expired"
 )
 
 // Error is the Feather error object.
@@ -0,0 +1,128 @@
+package feather
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// FactorStatus represents the status of a factor.
+type FactorStatus string
+
+const (
+	// The factor has been enrolled but a code has not yet been verified.
+	FactorStatusUnverified = "unverified"
+
+	// A code generated by the factor has been verified and the factor
+	// may now be used to upgrade a session.
+	FactorStatusVerified = "verified"
+)
+
+// FactorType represents the type of a second authentication factor.
+type FactorType string
+
+const (
+	// A time-based one-time password authenticator (RFC 6238).
+	FactorTypeTOTP = "totp"
+)
+
+// Factor is the Feather factor object.
+// https://feather.id/docs/reference/api#factorObject
+type Factor struct {
+	ID         string       `json:"id"`
+	Object     string       `json:"object"`
+	Type       FactorType   `json:"type"`
+	Status     FactorStatus `json:"status"`
+	UserID     string       `json:"user_id"`
+	Secret     *string      `json:"secret"`
+	URI        *string      `json:"uri"`
+	CreatedAt  time.Time    `json:"created_at"`
+	VerifiedAt *time.Time   `json:"verified_at"`
+}
+
+// FactorList is a list of Feather factor objects.
+// https://feather.id/docs/reference/api#pagination
+type FactorList struct {
+	ListMeta
+	Data []*Factor `json:"data"`
+}
+
+// Factors provides an interface for accessing Feather API factor objects.
+// https://feather.id/docs/reference/api#factors
+type Factors interface {
+	Create(params FactorsCreateParams) (*Factor, error)
+	Delete(id string) (*Factor, error)
+	List(params FactorsListParams) (*FactorList, error)
+	Verify(id string, params FactorsVerifyParams) (*Factor, error)
+}
+
+type factors struct {
+	gateway gateway
+}
+
+func newFactorsResource(g gateway) factors {
+	return factors{
+		gateway: g,
+	}
+}
+
+// Create a new factor, enrolling an authenticator for a user.
+// The secret and otpauth URI are only returned when the factor is created.
+// https://feather.id/docs/reference/api#createFactor
+func (f factors) Create(params FactorsCreateParams) (*Factor, error) {
+	var factor Factor
+	if err := f.gateway.sendRequest(http.MethodPost, pathFactors, params, &factor); err != nil {
+		return nil, err
+	}
+	return &factor, nil
+}
+
+// FactorsCreateParams ...
+type FactorsCreateParams struct {
+	Type   FactorType `json:"type"`
+	UserID *string    `json:"user_id"`
+	Issuer *string    `json:"issuer"`
+}
+
+// Delete a factor.
+// https://feather.id/docs/reference/api#deleteFactor
+func (f factors) Delete(id string) (*Factor, error) {
+	var factor Factor
+	path := strings.Join([]string{pathFactors, id}, "/")
+	if err := f.gateway.sendRequest(http.MethodDelete, path, nil, &factor); err != nil {
+		return nil, err
+	}
+	return &factor, nil
+}
+
+// List a user's factors.
+// https://feather.id/docs/reference/api#listFactors
+func (f factors) List(params FactorsListParams) (*FactorList, error) {
+	var factorList FactorList
+	if err := f.gateway.sendRequest(http.MethodGet, pathFactors, params, &factorList); err != nil {
+		return nil, err
+	}
+	return &factorList, nil
+}
+
+// FactorsListParams ...
+type FactorsListParams struct {
+	ListParams
+	UserID *string `json:"user_id"`
+}
+
+// Verify a factor by providing a code generated by the enrolled authenticator.
+// https://feather.id/docs/reference/api#verifyFactor
+func (f factors) Verify(id string, params FactorsVerifyParams) (*Factor, error) {
+	var factor Factor
+	path := strings.Join([]string{pathFactors, id, "verify"}, "/")
+	if err := f.gateway.sendRequest(http.MethodPost, path, params, &factor); err != nil {
+		return nil, err
+	}
+	return &factor, nil
+}
+
+// FactorsVerifyParams ...
+type FactorsVerifyParams struct {
+	VerificationCode *string `json:"verification_code"`
+}
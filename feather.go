@@ -9,9 +9,14 @@ import "net/http"
 // You should instantiate and use a client to send requests to
 // the Feather API.
 type Client struct {
-	Credentials Credentials
-	Sessions    Sessions
-	Users       Users
+	Authenticators Authenticators
+	Credentials    Credentials
+	Factors        Factors
+	Sessions       Sessions
+	Users          Users
+
+	// userCache is shared with Users so helpers which modify users can invalidate them.
+	userCache *clientUserCache
 }
 
 // A Config provides extra configuration to intialize a Feather client with.
@@ -23,6 +28,13 @@ type Config struct {
 	Port       *string
 	BasePath   *string
 	HTTPClient *http.Client
+
+	// RequestEncoding is the format of request bodies: RequestEncodingForm (the default)
+	// or RequestEncodingJSON. Query parameters are always form encoded.
+	RequestEncoding *string
+
+	// UserCache is optional. If set, Users.Retrieve reads through the cache.
+	UserCache UserCache
 }
 
 // New creates a new instance of the Feather client.
@@ -37,9 +49,13 @@ func New(apiKey string, cfgs ...*Config) Client {
 		apiKey: apiKey,
 		config: cfg,
 	}
+	userCache := newClientUserCache(cfg.UserCache)
 	return Client{
-		Credentials: newCredentialsResource(g),
-		Sessions:    newSessionsResource(g),
-		Users:       newUsersResource(g),
+		Authenticators: newAuthenticatorsResource(g),
+		Credentials:    newCredentialsResource(g),
+		Factors:        newFactorsResource(g),
+		Sessions:       newSessionsResource(g),
+		Users:          newUsersResource(g, userCache),
+		userCache:      userCache,
 	}
 }
@@ -0,0 +1,40 @@
+package feathertest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/feather-id/feather-go/feathertest"
+	"github.com/stretchr/testify/assert"
+)
+
+// The RFC 6238 appendix B test secret ("12345678901234567890") encoded as base32
+const sampleSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTP(t *testing.T) {
+	samples := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, expected := range samples {
+		code, err := feathertest.TOTP(sampleSecret, time.Unix(unix, 0))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, code)
+	}
+}
+
+func TestTOTP_LowercaseAndPadded(t *testing.T) {
+	code, err := feathertest.TOTP("gezdgnbvgy3tqojqgezdgnbvgy3tqojq====", time.Unix(59, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, "287082", code)
+}
+
+func TestTOTP_InvalidSecret(t *testing.T) {
+	code, err := feathertest.TOTP("not base32!", time.Now())
+	assert.Equal(t, "", code)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,170 @@
+package feather
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NullString returns a value which clears a string field when sent as a param,
+// eg UsersUpdateParams{Username: feather.NullString()}. It is sent as an explicit
+// empty form value, or as null in JSON request bodies, rather than being omitted
+// like a nil field.
+func NullString() *string {
+	s := nullString
+	return &s
+}
+
+// nullString is the value of the strings returned by NullString. It contains a
+// NUL byte so it cannot be mistaken for a real param value.
+const nullString = "\x00feather:null"
+
+// formPair is a single url-encoded key/value pair.
+type formPair struct {
+	key   string
+	value string
+}
+
+// urlEncodeData encodes a params struct (or map) as application/x-www-form-urlencoded data.
+// Nil fields are omitted, slices of scalars are sent as "key[]", slices of objects as
+// "key[i][field]", nested objects and maps as "parent[child]", and times in RFC 3339 format.
+// Keys are emitted in field order, with map keys sorted, so the output is deterministic.
+func urlEncodeData(data interface{}) (string, error) {
+	if data == nil {
+		return "", nil
+	}
+	var pairs []formPair
+	if err := encodeFormValue(&pairs, "", reflect.ValueOf(data)); err != nil {
+		return "", err
+	}
+	encPairs := make([]string, len(pairs))
+	for i, p := range pairs {
+		encPairs[i] = url.QueryEscape(p.key) + "=" + url.QueryEscape(p.value)
+	}
+	return strings.Join(encPairs, "&"), nil
+}
+
+func encodeFormValue(pairs *[]formPair, key string, v reflect.Value) error {
+	// Dereference pointers and interfaces, omitting nil values
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		return addFormPair(pairs, key, v.Interface().(time.Time).Format(time.RFC3339Nano))
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return encodeFormStruct(pairs, key, v)
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot encode map with %v keys", v.Type().Key())
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			if err := encodeFormValue(pairs, nestFormKey(key, k.String()), v.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		if key == "" {
+			return fmt.Errorf("cannot encode %v as form data", v.Type())
+		}
+		for i := 0; i < v.Len(); i++ {
+			elemKey := key + "[]"
+			if !isFormScalar(v.Index(i)) {
+				elemKey = nestFormKey(key, strconv.Itoa(i))
+			}
+			if err := encodeFormValue(pairs, elemKey, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.String:
+		if v.String() == nullString {
+			return addFormPair(pairs, key, "")
+		}
+		return addFormPair(pairs, key, v.String())
+	case reflect.Bool:
+		return addFormPair(pairs, key, strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return addFormPair(pairs, key, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return addFormPair(pairs, key, strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		return addFormPair(pairs, key, strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()))
+	}
+	return fmt.Errorf("cannot encode %v as form data", v.Type())
+}
+
+func encodeFormStruct(pairs *[]formPair, key string, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		// Embedded structs without a tag are flattened into their parent
+		if field.Anonymous && name == "" {
+			if err := encodeFormValue(pairs, key, v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Only encode fields with a json tag
+		if name == "" {
+			continue
+		}
+		if err := encodeFormValue(pairs, nestFormKey(key, name), v.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addFormPair(pairs *[]formPair, key string, value string) error {
+	if key == "" {
+		return fmt.Errorf("cannot encode a scalar value without a key")
+	}
+	*pairs = append(*pairs, formPair{key: key, value: value})
+	return nil
+}
+
+// nestFormKey nests the child key within the parent (eg "metadata" and "foo" become "metadata[foo]").
+func nestFormKey(parent string, child string) string {
+	if parent == "" {
+		return child
+	}
+	return parent + "[" + child + "]"
+}
+
+// isFormScalar reports whether the value is encoded as a single value rather than an object.
+func isFormScalar(v reflect.Value) bool {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Type() == timeType {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return false
+	}
+	return true
+}
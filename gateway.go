@@ -1,18 +1,27 @@
 package feather
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
-	"net/url"
-	"reflect"
 	"strings"
 )
 
+// Formats in which request bodies can be sent to the Feather API (see Config.RequestEncoding).
 const (
-	contentType     string = "application/x-www-form-urlencoded"
+	// Request bodies are sent as application/x-www-form-urlencoded data.
+	RequestEncodingForm = "form"
+
+	// Request bodies are sent as application/json data.
+	RequestEncodingJSON = "json"
+)
+
+const (
+	contentTypeForm string = "application/x-www-form-urlencoded"
+	contentTypeJSON string = "application/json"
 	defaultProtocol string = "https"
 	defaultHost     string = "api.feather.id"
 	defaultPort     string = "443"
@@ -44,20 +53,52 @@ func (g gateway) sendRequest(method string, path string, data interface{}, write
 }
 
 func (g gateway) buildRequest(method string, path string, data interface{}) (*http.Request, error) {
-	url := buildRequestURL(method, path, data, g.config)
+	url, err := buildRequestURL(method, path, data, g.config)
+	if err != nil {
+		return nil, err
+	}
+	useJSON := g.config.RequestEncoding != nil && *g.config.RequestEncoding == RequestEncodingJSON
 	var body io.Reader
 	if method == http.MethodPost {
-		body = buildRequestBody(data)
+		if useJSON {
+			body, err = buildJSONRequestBody(data)
+		} else {
+			body, err = buildRequestBody(data)
+		}
+		if err != nil {
+			return nil, err
+		}
 	}
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
 	req.SetBasicAuth(g.apiKey, "")
-	req.Header.Set("Content-Type", contentType)
+	if !useJSON {
+		req.Header.Set("Content-Type", contentTypeForm)
+	} else if body != nil {
+		req.Header.Set("Content-Type", contentTypeJSON)
+	}
+	if h, ok := data.(headerParams); ok {
+		for k, vs := range h.requestHeaders() {
+			for _, v := range vs {
+				req.Header.Add(k, v)
+			}
+		}
+	}
 	return req, nil
 }
 
+// headerParams is implemented by params which send some of their values as HTTP headers.
+type headerParams interface {
+	requestHeaders() http.Header
+}
+
+// etagReceiver is implemented by objects which record the ETag response header.
+type etagReceiver interface {
+	setETag(etag string)
+}
+
 func (g gateway) getClient() *http.Client {
 	if g.config.HTTPClient != nil {
 		return g.config.HTTPClient
@@ -65,7 +106,7 @@ func (g gateway) getClient() *http.Client {
 	return http.DefaultClient
 }
 
-func buildRequestURL(method string, path string, data interface{}, cfg Config) string {
+func buildRequestURL(method string, path string, data interface{}, cfg Config) (string, error) {
 	protocol := defaultProtocol
 	if cfg.Protocol != nil {
 		protocol = *cfg.Protocol
@@ -84,78 +125,77 @@ func buildRequestURL(method string, path string, data interface{}, cfg Config) s
 	}
 	query := ""
 	if method == http.MethodGet {
-		query = "?" + urlEncodeData(data)
+		encData, err := urlEncodeData(data)
+		if err != nil {
+			return "", err
+		}
+		query = "?" + encData
 	}
-	return fmt.Sprintf("%v://%v:%v%v%v%v", protocol, host, port, basePath, path, query)
+	return fmt.Sprintf("%v://%v:%v%v%v%v", protocol, host, port, basePath, path, query), nil
 }
 
-func buildRequestBody(data interface{}) io.Reader {
-	encData := urlEncodeData(data)
+func buildRequestBody(data interface{}) (io.Reader, error) {
+	encData, err := urlEncodeData(data)
+	if err != nil {
+		return nil, err
+	}
 	var body io.Reader
 	if encData != "" {
 		body = strings.NewReader(encData)
 	}
-	return body
+	return body, nil
 }
 
-func urlEncodeData(data interface{}) string {
+func buildJSONRequestBody(data interface{}) (io.Reader, error) {
 	if data == nil {
-		return ""
+		return nil, nil
 	}
+	encData, err := jsonEncodeData(data)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(encData), nil
+}
 
-	// Joins two strings of url-encoded data together
-	joinEncData := func(a string, b string) string {
-		if a == "" {
-			return b
-		} else if b == "" {
-			return a
-		}
-		return a + "&" + b
-	}
-
-	// Assumes data is a struct
-	// Iterate through the fields of the struct and url-encodes the field/value pairs
-	encData := ""
-	rData := reflect.ValueOf(data)
-	rDataType := reflect.TypeOf(data)
-	urlVals := url.Values{}
-	for i := 0; i < rData.NumField(); i++ {
-
-		// Only encode non-nil values
-		if rData.Field(i).Kind() != reflect.Ptr || !rData.Field(i).IsNil() {
-			if rData.Field(i).Kind() == reflect.Struct {
-
-				// If the value is a struct, recursively flatten the request
-				encData = joinEncData(encData, urlEncodeData(rData.Field(i).Interface()))
-
-			} else if tag := rDataType.Field(i).Tag.Get("json"); tag != "" {
-				// Otherwise, get the json tag for this value...
-				var rDataField = rData.Field(i)
-
-				// Dereference pointer values
-				if rDataField.Kind() == reflect.Ptr {
-					rDataField = rDataField.Elem()
-				}
-
-				if rDataField.Kind() == reflect.Map {
-					// If the value is a map, create a special key
-					iter := rDataField.MapRange()
-					for iter.Next() {
-						k := iter.Key().Interface()
-						v := iter.Value().Interface()
-						urlVals.Add(fmt.Sprintf("%v[%v]", tag, k), fmt.Sprintf("%v", v))
-					}
-				} else {
-					// Otherwise, just cast the value to a string
-					urlVals.Add(tag, fmt.Sprintf("%v", rDataField.Interface()))
-				}
+// jsonEncodeData encodes the data with encoding/json semantics, except that
+// unset (nil) fields are omitted rather than sent as null, like the form encoding,
+// and NullString values are sent as null.
+func jsonEncodeData(data interface{}) ([]byte, error) {
+	encData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(encData))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(omitNulls(v))
+}
+
+// omitNulls recursively removes null values from decoded JSON objects,
+// and replaces NullString values with explicit nulls.
+func omitNulls(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range v {
+			if elem == nil {
+				delete(v, k)
+			} else {
+				v[k] = omitNulls(elem)
 			}
 		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = omitNulls(elem)
+		}
+	case string:
+		if v == nullString {
+			return json.RawMessage("null")
+		}
 	}
-
-	// Encode the collected key/vale pairs
-	encData = joinEncData(encData, urlVals.Encode())
-	return encData
+	return v
 }
 
 func parseResponse(resp *http.Response, into interface{}) error {
@@ -183,5 +223,11 @@ func parseResponse(resp *http.Response, into interface{}) error {
 		}
 		return ferr
 	}
-	return json.Unmarshal(bytes, into)
+	if err := json.Unmarshal(bytes, into); err != nil {
+		return err
+	}
+	if r, ok := into.(etagReceiver); ok && resp.Header.Get("ETag") != "" {
+		r.setETag(resp.Header.Get("ETag"))
+	}
+	return nil
 }
@@ -0,0 +1,81 @@
+package feather
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGateway_UnsupportedParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %v %v", r.Method, r.URL)
+	}))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	params := []interface{}{
+		struct {
+			Labels map[int]string `json:"labels"`
+		}{Labels: map[int]string{1: "foo"}},
+		struct {
+			Events chan string `json:"events"`
+		}{Events: make(chan string)},
+		struct {
+			Callback func() `json:"callback"`
+		}{Callback: func() {}},
+	}
+	for _, encoding := range []string{RequestEncodingForm, RequestEncodingJSON} {
+		g := gateway{
+			apiKey: "fooKey",
+			config: Config{
+				Protocol:        String("http"),
+				Host:            String(host),
+				Port:            String(port),
+				RequestEncoding: String(encoding),
+			},
+		}
+		for _, method := range []string{http.MethodGet, http.MethodPost} {
+			for i, p := range params {
+				// encoding/json supports integer map keys
+				if i == 0 && encoding == RequestEncodingJSON && method == http.MethodPost {
+					continue
+				}
+				var user User
+				err := g.sendRequest(method, pathUsers, p, &user)
+				ferr, ok := err.(Error)
+				assert.True(t, ok, "%v %v %T", encoding, method, p)
+				assert.Equal(t, ErrorTypeValidation, ferr.Type)
+			}
+		}
+	}
+}
+
+type sampleJSONMarshaler struct{}
+
+func (*sampleJSONMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`"custom"`), nil
+}
+
+func TestJSONEncodeData(t *testing.T) {
+	encData, err := jsonEncodeData(&struct {
+		Untagged  string
+		Empty     string              `json:"empty,omitempty"`
+		Count     int                 `json:"count,string"`
+		Custom    sampleJSONMarshaler `json:"custom"`
+		Unset     *string             `json:"unset"`
+		Cleared   *string             `json:"cleared"`
+		Nested    map[string]*string  `json:"nested"`
+		Ignored   string              `json:"-"`
+		unexposed string
+	}{
+		Untagged: "foo",
+		Count:    3,
+		Cleared:  NullString(),
+		Nested:   map[string]*string{"a": String("b"), "c": nil},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"Untagged":"foo","cleared":null,"count":"3","custom":"custom","nested":{"a":"b"}}`, string(encData))
+}
@@ -0,0 +1,140 @@
+// Package grpcauth authenticates gRPC requests with Feather sessions.
+//
+// Clients send their session token in the "authorization" metadata, optionally
+// prefixed with "Bearer ". The interceptors validate the token and add the session
+// to the request context, where handlers can read it with feather.SessionFromContext.
+//
+// The interceptors live in this package rather than in feather itself so only
+// applications which import grpcauth depend on google.golang.org/grpc.
+package grpcauth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/feather-id/feather-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMetadataKey is the metadata key the session token is read from.
+const DefaultMetadataKey = "authorization"
+
+// Authenticator provides gRPC server interceptors which require a valid session.
+//
+// Feather errors are mapped to gRPC status codes: a missing or invalid session token,
+// or a session which is expired, revoked or otherwise inactive, is Unauthenticated,
+// an unreachable or rate limited Feather API is Unavailable, and anything else is Internal.
+type Authenticator struct {
+	Sessions feather.Sessions
+
+	// MetadataKey defaults to "authorization".
+	MetadataKey string
+
+	// SkipMethods are full method names (eg "/grpc.health.v1.Health/Check")
+	// which do not require a session.
+	SkipMethods []string
+}
+
+// UnaryServerInterceptor returns an interceptor which authenticates unary requests.
+func (a Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if a.skip(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := a.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor which authenticates streams.
+func (a Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if a.skip(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := a.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream overrides the stream's context with one holding the session.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (a Authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	token := a.token(ctx)
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "No session token was provided")
+	}
+	session, err := a.Sessions.Validate(feather.SessionsValidateParams{
+		SessionToken: &token,
+	})
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	if session.Status != feather.SessionStatusActive {
+		return nil, status.Errorf(codes.Unauthenticated, "The session is %v", session.Status)
+	}
+	return feather.ContextWithSession(ctx, session), nil
+}
+
+func (a Authenticator) token(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	key := a.MetadataKey
+	if key == "" {
+		key = DefaultMetadataKey
+	}
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	token := strings.TrimSpace(values[0])
+	if len(token) > len("Bearer ") && strings.EqualFold(token[:len("Bearer ")], "Bearer ") {
+		token = strings.TrimSpace(token[len("Bearer "):])
+	}
+	return token
+}
+
+func (a Authenticator) skip(fullMethod string) bool {
+	for _, method := range a.SkipMethods {
+		if method == fullMethod {
+			return true
+		}
+	}
+	return false
+}
+
+func statusFromError(err error) error {
+	ferr, ok := err.(feather.Error)
+	if !ok {
+		return status.Error(codes.Internal, err.Error())
+	}
+	switch ferr.Code {
+	case feather.ErrorCodeSessionTokenInvalid, feather.ErrorCodeSessionTokenExpired, feather.ErrorCodeSessionExpired,
+		feather.ErrorCodeSessionInactive, feather.ErrorCodeSessionRevoked:
+		return status.Error(codes.Unauthenticated, ferr.Message)
+	}
+	switch ferr.Type {
+	case feather.ErrorTypeAPIConnection, feather.ErrorTypeRateLimit:
+		return status.Error(codes.Unavailable, ferr.Message)
+	}
+	return status.Error(codes.Internal, ferr.Message)
+}
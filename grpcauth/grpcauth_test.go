@@ -0,0 +1,147 @@
+package grpcauth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/feather-id/feather-go"
+	"github.com/feather-id/feather-go/grpcauth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var sampleSession = &feather.Session{
+	ID:     "SES_foo",
+	Status: feather.SessionStatusActive,
+	UserID: "USR_foo",
+}
+
+// fakeSessions validates session tokens with a function instead of the Feather API.
+type fakeSessions struct {
+	feather.Sessions
+	validate func(token string) (*feather.Session, error)
+}
+
+func (s fakeSessions) Validate(params feather.SessionsValidateParams) (*feather.Session, error) {
+	return s.validate(*params.SessionToken)
+}
+
+func sampleAuthenticator() grpcauth.Authenticator {
+	return grpcauth.Authenticator{
+		Sessions: fakeSessions{validate: func(token string) (*feather.Session, error) {
+			switch token {
+			case "valid":
+				return sampleSession, nil
+			case "revoked":
+				return &feather.Session{ID: "SES_bar", Status: feather.SessionStatusRevoked}, nil
+			case "inactive":
+				return nil, feather.Error{Type: feather.ErrorTypeValidation, Code: feather.ErrorCodeSessionInactive, Message: "inactive"}
+			case "unreachable":
+				return nil, feather.Error{Type: feather.ErrorTypeAPIConnection, Message: "unreachable"}
+			case "broken":
+				return nil, errors.New("broken")
+			}
+			return nil, feather.Error{Type: feather.ErrorTypeValidation, Code: feather.ErrorCodeSessionTokenInvalid, Message: "invalid"}
+		}},
+		SkipMethods: []string{"/grpc.health.v1.Health/Check"},
+	}
+}
+
+func incomingContext(kv ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(kv...))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := sampleAuthenticator().UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/foo.Service/Method"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		session, ok := feather.SessionFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, sampleSession, session)
+		return "ok", nil
+	}
+
+	for _, value := range []string{"valid", "Bearer valid", "bearer  valid"} {
+		resp, err := interceptor(incomingContext("authorization", value), "req", info, handler)
+		assert.Nil(t, err)
+		assert.Equal(t, "ok", resp)
+	}
+
+	tests := map[string]codes.Code{
+		"":            codes.Unauthenticated,
+		"invalid":     codes.Unauthenticated,
+		"revoked":     codes.Unauthenticated,
+		"inactive":    codes.Unauthenticated,
+		"unreachable": codes.Unavailable,
+		"broken":      codes.Internal,
+	}
+	for token, code := range tests {
+		resp, err := interceptor(incomingContext("authorization", token), "req", info, handler)
+		assert.Nil(t, resp)
+		assert.Equal(t, code, status.Code(err), token)
+	}
+
+	_, err := interceptor(context.Background(), "req", info, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestUnaryServerInterceptor_SkipMethods(t *testing.T) {
+	interceptor := sampleAuthenticator().UnaryServerInterceptor()
+	resp, err := interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			_, ok := feather.SessionFromContext(ctx)
+			assert.False(t, ok)
+			return "ok", nil
+		})
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", resp)
+}
+
+func TestUnaryServerInterceptor_MetadataKey(t *testing.T) {
+	authenticator := sampleAuthenticator()
+	authenticator.MetadataKey = "x-session-token"
+	interceptor := authenticator.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/foo.Service/Method"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	_, err := interceptor(incomingContext("x-session-token", "valid"), "req", info, handler)
+	assert.Nil(t, err)
+	_, err = interceptor(incomingContext("authorization", "valid"), "req", info, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := sampleAuthenticator().StreamServerInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/foo.Service/Stream"}
+	called := false
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		called = true
+		session, ok := feather.SessionFromContext(ss.Context())
+		assert.True(t, ok)
+		assert.Equal(t, sampleSession, session)
+		return nil
+	}
+
+	err := interceptor(nil, fakeServerStream{ctx: incomingContext("authorization", "Bearer valid")}, info, handler)
+	assert.Nil(t, err)
+	assert.True(t, called)
+
+	called = false
+	err = interceptor(nil, fakeServerStream{ctx: incomingContext("authorization", "revoked")}, info, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.False(t, called)
+}
@@ -1,5 +1,7 @@
 package feather
 
+import "time"
+
 // ListMeta ...
 // https://feather.id/docs/reference/api#pagination
 type ListMeta struct {
@@ -15,3 +17,11 @@ type ListParams struct {
 	StartingAfter *string `json:"starting_after"`
 	EndingBefore  *string `json:"ending_before"`
 }
+
+// TimeRangeParams filters a list by a timestamp. Each bound is optional.
+type TimeRangeParams struct {
+	GreaterThan        *time.Time `json:"gt"`
+	GreaterThanOrEqual *time.Time `json:"gte"`
+	LessThan           *time.Time `json:"lt"`
+	LessThanOrEqual    *time.Time `json:"lte"`
+}
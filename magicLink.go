@@ -0,0 +1,126 @@
+package feather
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+)
+
+const (
+	defaultMagicLinkCredentialParam = "credential_id"
+	defaultMagicLinkCodeParam       = "code"
+)
+
+// MagicLinkHandler is an http.Handler which completes an email magic link sign-in.
+//
+// The link sent to the user (via a CredentialTypeEmail credential created with a
+// TemplateName) should point at this handler and include the credential ID and
+// verification code as query parameters. The handler verifies the credential,
+// creates a session, stores the session token in a cookie and redirects the user
+// to SuccessURL. If any step fails, the user is redirected to FailureURL with the
+// Feather error code in the "error" query parameter.
+type MagicLinkHandler struct {
+	Client     Client
+	SuccessURL string
+	FailureURL string
+
+	// CredentialParam and CodeParam name the query parameters holding the
+	// credential ID and verification code. They default to "credential_id" and "code".
+	CredentialParam string
+	CodeParam       string
+
+	// CookieName defaults to "feather_session".
+	CookieName     string
+	CookieDomain   string
+	CookiePath     string
+	InsecureCookie bool
+}
+
+func (h MagicLinkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	session, err := h.complete(r)
+	if err != nil {
+		code := ErrorCodeCredentialInvalid
+		if ferr, ok := err.(Error); ok && ferr.Code != "" {
+			code = ferr.Code
+		}
+		http.Redirect(w, r, withQueryParam(h.FailureURL, "error", string(code)), http.StatusFound)
+		return
+	}
+	cookie := SessionCookie{
+		Name:     h.CookieName,
+		Domain:   h.CookieDomain,
+		Path:     h.CookiePath,
+		Insecure: h.InsecureCookie,
+	}
+	cookie.Set(w, session)
+	http.Redirect(w, r, h.SuccessURL, http.StatusFound)
+}
+
+func (h MagicLinkHandler) complete(r *http.Request) (*Session, error) {
+	query := r.URL.Query()
+	credentialID := query.Get(stringOrDefault(h.CredentialParam, defaultMagicLinkCredentialParam))
+	code := query.Get(stringOrDefault(h.CodeParam, defaultMagicLinkCodeParam))
+	if credentialID == "" || code == "" {
+		return nil, Error{
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeParameterMissing,
+			Message: "The magic link is missing the credential ID or verification code",
+		}
+	}
+
+	// Verify the credential
+	credential, err := h.Client.Credentials.Update(credentialID, CredentialsUpdateParams{
+		VerificationCode: &code,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if credential.Status != CredentialStatusValid || credential.Token == nil {
+		return nil, Error{
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeCredentialStatusNotValid,
+			Message: "The magic link credential is not valid",
+		}
+	}
+
+	// Create a session, recording the device it was created from
+	params := SessionsCreateParams{
+		CredentialToken: credential.Token,
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		params.IPAddress = &host
+	}
+	if userAgent := r.UserAgent(); userAgent != "" {
+		params.UserAgent = &userAgent
+	}
+	session, err := h.Client.Sessions.Create(params)
+	if err != nil {
+		return nil, err
+	}
+	if session.Token == nil {
+		return nil, Error{
+			Type:    ErrorTypeAPI,
+			Code:    ErrorCodeSessionTokenInvalid,
+			Message: "The created session did not include a session token",
+		}
+	}
+	return session, nil
+}
+
+func withQueryParam(rawURL string, key string, value string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func stringOrDefault(v string, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
@@ -2,6 +2,16 @@ package feather
 
 import "time"
 
+// Bool returns a pointer to the provided bool value.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Int returns a pointer to the provided int value.
+func Int(v int) *int {
+	return &v
+}
+
 // String returns a pointer to the provided string value.
 func String(v string) *string {
 	return &v
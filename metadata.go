@@ -0,0 +1,130 @@
+package feather
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MetadataOperationType represents the type of an atomic metadata operation.
+type MetadataOperationType string
+
+const (
+	// Set the key to the value.
+	MetadataOperationTypeSet = "set"
+
+	// Delete the key.
+	MetadataOperationTypeDelete = "delete"
+
+	// Add an integer amount to the key's numeric value. A missing key is treated as 0.
+	MetadataOperationTypeIncrement = "increment"
+
+	// Set the key to the value only if its current value matches the expected value.
+	MetadataOperationTypeCompareAndSet = "compare_and_set"
+)
+
+// MetadataOperation is a single atomic operation on a user's metadata.
+// Use MetadataSet, MetadataDelete, MetadataIncrement and MetadataCompareAndSet to create operations.
+type MetadataOperation struct {
+	Op    MetadataOperationType `json:"op"`
+	Key   string                `json:"key"`
+	Value *string               `json:"value"`
+	By    *int64                `json:"by"`
+
+	// Expected is the value the key must currently hold for a compare-and-set.
+	// If nil, the key must not currently exist.
+	Expected *string `json:"expected"`
+}
+
+// MetadataSet returns an operation which sets the key to the value.
+func MetadataSet(key string, value string) MetadataOperation {
+	return MetadataOperation{Op: MetadataOperationTypeSet, Key: key, Value: &value}
+}
+
+// MetadataDelete returns an operation which deletes the key.
+func MetadataDelete(key string) MetadataOperation {
+	return MetadataOperation{Op: MetadataOperationTypeDelete, Key: key}
+}
+
+// MetadataIncrement returns an operation which adds by to the key's numeric value.
+func MetadataIncrement(key string, by int64) MetadataOperation {
+	return MetadataOperation{Op: MetadataOperationTypeIncrement, Key: key, By: &by}
+}
+
+// MetadataCompareAndSet returns an operation which sets the key to the value
+// only if it currently holds the expected value (or does not exist, if expected is nil).
+func MetadataCompareAndSet(key string, expected *string, value string) MetadataOperation {
+	return MetadataOperation{Op: MetadataOperationTypeCompareAndSet, Key: key, Value: &value, Expected: expected}
+}
+
+func (o MetadataOperation) validate() error {
+	missing := func(param string) error {
+		return Error{
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeParameterMissing,
+			Message: fmt.Sprintf("The %v metadata operation requires a %v", o.Op, param),
+		}
+	}
+	if o.Key == "" {
+		return missing("key")
+	}
+	switch o.Op {
+	case MetadataOperationTypeSet, MetadataOperationTypeCompareAndSet:
+		if o.Value == nil {
+			return missing("value")
+		}
+	case MetadataOperationTypeIncrement:
+		if o.By == nil {
+			return missing("increment amount")
+		}
+	case MetadataOperationTypeDelete:
+	default:
+		return Error{
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeParameterInvalid,
+			Message: fmt.Sprintf("The metadata operation %q is not supported", o.Op),
+		}
+	}
+	return nil
+}
+
+// PatchMetadata atomically applies the operations to a user's metadata.
+// Either every operation is applied or none are. If a compare-and-set does not
+// match, an Error with code ErrorCodeMetadataConflict is returned, and if the user was
+// modified after IfUnmodifiedSince, an Error with code ErrorCodePreconditionFailed is
+// returned. In either case the caller should re-read the user and retry.
+// https://feather.id/docs/reference/api#patchUserMetadata
+func (u users) PatchMetadata(id string, params UsersPatchMetadataParams) (*User, error) {
+	for _, op := range params.Operations {
+		if err := op.validate(); err != nil {
+			return nil, err
+		}
+	}
+	var user User
+	path := strings.Join([]string{pathUsers, id, "metadata"}, "/")
+	if err := u.gateway.sendRequest(http.MethodPost, path, params, &user); err != nil {
+		u.invalidateIfStale(id, err)
+		return nil, err
+	}
+	u.invalidate(id)
+	return &user, nil
+}
+
+// UsersPatchMetadataParams ...
+type UsersPatchMetadataParams struct {
+	Operations []MetadataOperation `json:"operations"`
+
+	// IfUnmodifiedSince is typically the UpdatedAt of the last read of the user.
+	// It is sent as the If-Unmodified-Since header, truncated to whole seconds,
+	// as in UsersUpdateParams.
+	IfUnmodifiedSince *time.Time `json:"-"`
+}
+
+func (p UsersPatchMetadataParams) requestHeaders() http.Header {
+	header := http.Header{}
+	if p.IfUnmodifiedSince != nil {
+		header.Set("If-Unmodified-Since", formatIfUnmodifiedSince(*p.IfUnmodifiedSince))
+	}
+	return header
+}
@@ -0,0 +1,231 @@
+package feather
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetadataBindingError reports the metadata keys which could not be converted,
+// with the conversion error for each key.
+type MetadataBindingError struct {
+	Errors map[string]error
+}
+
+func (e MetadataBindingError) Error() string {
+	var keys []string
+	for k := range e.Errors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var msgs []string
+	for _, k := range keys {
+		msgs = append(msgs, fmt.Sprintf("%v: %v", k, e.Errors[k]))
+	}
+	return "Failed to bind metadata (" + strings.Join(msgs, "; ") + ")"
+}
+
+// DecodeMetadata decodes a user's metadata into the struct pointed to by v.
+//
+// Each exported field is read from the metadata key named by its `metadata` struct tag,
+// or the field name if it has no tag. Fields tagged "-" are ignored, and fields whose key
+// is missing are left unchanged. Strings, bools, integers, floats, time.Time (RFC 3339),
+// time.Duration and encoding.TextUnmarshaler types are parsed from their string form;
+// any other type (eg structs, slices and maps) is parsed as JSON.
+//
+// Keys which fail to convert are reported together in a MetadataBindingError;
+// the remaining fields are still decoded.
+func DecodeMetadata(metadata map[string]string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("DecodeMetadata requires a non-nil pointer to a struct")
+	}
+	rv = rv.Elem()
+	bindingErr := MetadataBindingError{Errors: map[string]error{}}
+	for i := 0; i < rv.NumField(); i++ {
+		key, _, ok := metadataKey(rv.Type().Field(i))
+		if !ok {
+			continue
+		}
+		str, ok := metadata[key]
+		if !ok {
+			continue
+		}
+		if err := decodeMetadataValue(str, rv.Field(i)); err != nil {
+			bindingErr.Errors[key] = err
+		}
+	}
+	if len(bindingErr.Errors) > 0 {
+		return bindingErr
+	}
+	return nil
+}
+
+// EncodeMetadata encodes the struct v into metadata which can be sent as
+// UsersUpdateParams.Metadata. Fields are named and formatted as in DecodeMetadata.
+// Fields tagged with the "omitempty" option are skipped when they hold their zero value,
+// and nil pointers are always skipped so their keys are left missing when decoded.
+func EncodeMetadata(v interface{}) (*map[string]string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("EncodeMetadata requires a struct or a pointer to a struct")
+	}
+	metadata := map[string]string{}
+	bindingErr := MetadataBindingError{Errors: map[string]error{}}
+	for i := 0; i < rv.NumField(); i++ {
+		key, omitEmpty, ok := metadataKey(rv.Type().Field(i))
+		if !ok {
+			continue
+		}
+		field := rv.Field(i)
+		if (omitEmpty && field.IsZero()) || (field.Kind() == reflect.Ptr && field.IsNil()) {
+			continue
+		}
+		str, err := encodeMetadataValue(field)
+		if err != nil {
+			bindingErr.Errors[key] = err
+			continue
+		}
+		metadata[key] = str
+	}
+	if len(bindingErr.Errors) > 0 {
+		return nil, bindingErr
+	}
+	return &metadata, nil
+}
+
+// metadataKey returns the metadata key for a struct field and whether it should be omitted when empty.
+func metadataKey(field reflect.StructField) (string, bool, bool) {
+	if field.PkgPath != "" {
+		return "", false, false
+	}
+	tag := field.Tag.Get("metadata")
+	if tag == "-" {
+		return "", false, false
+	}
+	opts := strings.Split(tag, ",")
+	key := opts[0]
+	if key == "" {
+		key = field.Name
+	}
+	omitEmpty := false
+	for _, opt := range opts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return key, omitEmpty, true
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func decodeMetadataValue(str string, v reflect.Value) error {
+	// Allocate pointers so the value can be decoded into them
+	if v.Kind() == reflect.Ptr {
+		ptr := reflect.New(v.Type().Elem())
+		if err := decodeMetadataValue(str, ptr.Elem()); err != nil {
+			return err
+		}
+		v.Set(ptr)
+		return nil
+	}
+
+	switch {
+	case v.Type() == timeType:
+		t, err := time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			return fmt.Errorf("expected an RFC 3339 time but found %q", str)
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return fmt.Errorf("expected a duration but found %q", str)
+		}
+		v.SetInt(int64(d))
+		return nil
+	case reflect.PtrTo(v.Type()).Implements(textUnmarshalerType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(str)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return fmt.Errorf("expected a bool but found %q", str)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(str, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected %v but found %q", v.Type(), str)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(str, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected %v but found %q", v.Type(), str)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(str, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected %v but found %q", v.Type(), str)
+		}
+		v.SetFloat(n)
+	default:
+		if err := json.Unmarshal([]byte(str), v.Addr().Interface()); err != nil {
+			return fmt.Errorf("expected JSON for %v: %v", v.Type(), err)
+		}
+	}
+	return nil
+}
+
+func encodeMetadataValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Type() == timeType:
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), nil
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String(), nil
+	case v.Type().Implements(textMarshalerType):
+		bytes, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(bytes), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		bytes, err := json.Marshal(v.Interface())
+		return string(bytes), err
+	}
+}
@@ -1,8 +1,14 @@
 package feather
 
 const (
-	pathCredentials = "/credentials"
-	pathPublicKeys  = "/publicKeys"
-	pathSessions    = "/sessions"
-	pathUsers       = "/users"
+	pathAuthenticators        = "/authenticators"
+	pathCredentials           = "/credentials"
+	pathFactors               = "/factors"
+	pathPublicKeys            = "/publicKeys"
+	pathSessions              = "/sessions"
+	pathSessionsRevokeAll     = pathSessions + "/revoke_all"
+	pathUsers                 = "/users"
+	pathUsersImport           = pathUsers + "/import"
+	pathWebAuthnAssertions    = pathCredentials + "/webauthn/assertions"
+	pathWebAuthnRegistrations = pathCredentials + "/webauthn/registrations"
 )
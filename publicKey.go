@@ -12,7 +12,10 @@ import (
 func (s *sessions) getPublicKey(keyID string) (*rsa.PublicKey, error) {
 
 	// Check the cache
-	if publicKey, ok := s.cachedPublicKeys[keyID]; ok {
+	s.cachedPublicKeysMu.RLock()
+	publicKey, ok := s.cachedPublicKeys[keyID]
+	s.cachedPublicKeysMu.RUnlock()
+	if ok {
 		return publicKey, nil
 	}
 
@@ -43,12 +46,14 @@ func (s *sessions) getPublicKey(keyID string) (*rsa.PublicKey, error) {
 			return nil, err
 		}
 	}
-	publicKey, ok := parsedKey.(*rsa.PublicKey)
+	publicKey, ok = parsedKey.(*rsa.PublicKey)
 	if !ok {
 		return nil, fmt.Errorf("Failed to parse public key %v", keyID)
 	}
 
 	// Cache and return
+	s.cachedPublicKeysMu.Lock()
 	s.cachedPublicKeys[keyID] = publicKey
+	s.cachedPublicKeysMu.Unlock()
 	return publicKey, nil
 }
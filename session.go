@@ -2,16 +2,17 @@ package feather
 
 import (
 	"crypto/rsa"
-	"errors"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
-
-	jwt "github.com/dgrijalva/jwt-go"
 )
 
 const (
 	featherIssuer = "feather.id"
+
+	defaultRevokeAllConcurrency        = 4
+	revokeAllPageSize           uint32 = 100
 )
 
 // SessionStatus represents the status of a session.
@@ -31,13 +32,18 @@ const (
 // Session is the Feather session object.
 // https://feather.id/docs/reference/api#sessionObject
 type Session struct {
-	ID        string        `json:"id"`
-	Object    string        `json:"object"`
-	Status    SessionStatus `json:"status"`
-	Token     *string       `json:"token"`
-	UserID    string        `json:"user_id"`
-	CreatedAt time.Time     `json:"created_at"`
-	RevokedAt *time.Time    `json:"revoked_at"`
+	ID           string            `json:"id"`
+	Object       string            `json:"object"`
+	Status       SessionStatus     `json:"status"`
+	Token        *string           `json:"token"`
+	UserID       string            `json:"user_id"`
+	IPAddress    *string           `json:"ip_address"`
+	UserAgent    *string           `json:"user_agent"`
+	Metadata     map[string]string `json:"metadata"`
+	CreatedAt    time.Time         `json:"created_at"`
+	LastActiveAt *time.Time        `json:"last_active_at"`
+	ExpiresAt    *time.Time        `json:"expires_at"`
+	RevokedAt    *time.Time        `json:"revoked_at"`
 }
 
 // SessionList is a list of Feather session objects.
@@ -54,19 +60,25 @@ type Sessions interface {
 	List(params SessionsListParams) (*SessionList, error)
 	Retrieve(id string) (*Session, error)
 	Revoke(id string, params SessionsRevokeParams) (*Session, error)
+	RevokeAll(userID string, params SessionsRevokeAllParams) (int, error)
 	Upgrade(id string, params SessionsUpgradeParams) (*Session, error)
 	Validate(params SessionsValidateParams) (*Session, error)
 }
 
 type sessions struct {
-	gateway          gateway
-	cachedPublicKeys map[string]*rsa.PublicKey
+	gateway gateway
+
+	// cachedPublicKeysMu guards cachedPublicKeys, which is shared by copies of the
+	// resource and read by concurrent calls to Validate.
+	cachedPublicKeysMu *sync.RWMutex
+	cachedPublicKeys   map[string]*rsa.PublicKey
 }
 
 func newSessionsResource(g gateway) sessions {
 	return sessions{
-		gateway:          g,
-		cachedPublicKeys: map[string]*rsa.PublicKey{},
+		gateway:            g,
+		cachedPublicKeysMu: &sync.RWMutex{},
+		cachedPublicKeys:   map[string]*rsa.PublicKey{},
 	}
 }
 
@@ -82,10 +94,13 @@ func (s sessions) Create(params SessionsCreateParams) (*Session, error) {
 
 // SessionsCreateParams ...
 type SessionsCreateParams struct {
-	CredentialToken *string `json:"credential_token"`
+	CredentialToken *string            `json:"credential_token"`
+	IPAddress       *string            `json:"ip_address"`
+	UserAgent       *string            `json:"user_agent"`
+	Metadata        *map[string]string `json:"metadata"`
 }
 
-// List a user's sessions.
+// List sessions, either a single user's or across all users of the project.
 // https://feather.id/docs/reference/api#listSessions
 func (s sessions) List(params SessionsListParams) (*SessionList, error) {
 	var sessionList SessionList
@@ -98,7 +113,15 @@ func (s sessions) List(params SessionsListParams) (*SessionList, error) {
 // SessionsListParams ...
 type SessionsListParams struct {
 	ListParams
+
+	// UserID only lists the user's sessions. If nil, sessions of all users are listed.
 	UserID *string `json:"user_id"`
+
+	// Status only lists sessions with one of the statuses.
+	Status []SessionStatus `json:"status"`
+
+	CreatedAt *TimeRangeParams `json:"created_at"`
+	RevokedAt *TimeRangeParams `json:"revoked_at"`
 }
 
 // Retrieve a session.
@@ -128,6 +151,111 @@ type SessionsRevokeParams struct {
 	SessionToken *string `json:"session_token"`
 }
 
+// RevokeAll revokes all of a user's active sessions and returns the number revoked.
+// If the API does not support revoking sessions in bulk, the sessions are listed
+// and revoked one by one, returning the first error encountered. An unknown user
+// is reported with ErrorCodeNotFound.
+// https://feather.id/docs/reference/api#revokeAllSessions
+func (s sessions) RevokeAll(userID string, params SessionsRevokeAllParams) (int, error) {
+	data := struct {
+		SessionsRevokeAllParams
+		UserID *string `json:"user_id"`
+	}{params, String(userID)}
+	var result struct {
+		RevokedCount int `json:"revoked_count"`
+	}
+	err := s.gateway.sendRequest(http.MethodPost, pathSessionsRevokeAll, data, &result)
+	if ferr, ok := err.(Error); ok && ferr.Code == ErrorCodeNotFound {
+		// Either the bulk endpoint or the user does not exist, so only
+		// fall back to revoking sessions one by one if the user exists
+		var user User
+		path := strings.Join([]string{pathUsers, userID}, "/")
+		if err := s.gateway.sendRequest(http.MethodGet, path, nil, &user); err != nil {
+			return 0, err
+		}
+		return s.revokeEach(userID, params)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return result.RevokedCount, nil
+}
+
+// SessionsRevokeAllParams ...
+type SessionsRevokeAllParams struct {
+	// ExceptSessionID is a session which should not be revoked, typically the current session.
+	ExceptSessionID *string `json:"except_session_id"`
+
+	// Concurrency is the maximum number of revocations in flight if sessions must be
+	// revoked one by one. Defaults to 4.
+	Concurrency int `json:"-"`
+}
+
+func (s sessions) revokeEach(userID string, params SessionsRevokeAllParams) (int, error) {
+	// List every session before revoking any so pagination is not disturbed
+	var ids []string
+	pageSize := revokeAllPageSize
+	listParams := SessionsListParams{
+		UserID: String(userID),
+		Status: []SessionStatus{SessionStatusActive},
+	}
+	listParams.Limit = &pageSize
+	for {
+		sessionList, err := s.List(listParams)
+		if err != nil {
+			return 0, err
+		}
+		for _, session := range sessionList.Data {
+			if session.Status != SessionStatusActive {
+				continue
+			}
+			if params.ExceptSessionID != nil && session.ID == *params.ExceptSessionID {
+				continue
+			}
+			ids = append(ids, session.ID)
+		}
+		if len(sessionList.Data) < int(pageSize) {
+			break
+		}
+		listParams.StartingAfter = String(sessionList.Data[len(sessionList.Data)-1].ID)
+	}
+
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultRevokeAllConcurrency
+	}
+	queue := make(chan string)
+	go func() {
+		defer close(queue)
+		for _, id := range ids {
+			queue <- id
+		}
+	}()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	revoked := 0
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				_, err := s.Revoke(id, SessionsRevokeParams{})
+				mu.Lock()
+				if err == nil {
+					revoked++
+				} else if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return revoked, firstErr
+}
+
 // Upgrade a session.
 // https://feather.id/docs/reference/api#upgradeSession
 func (s sessions) Upgrade(id string, params SessionsUpgradeParams) (*Session, error) {
@@ -178,79 +306,24 @@ type SessionsValidateParams struct {
 }
 
 func (s *sessions) parseSessionToken(tokenStr string) (*Session, error) {
-	invalidTokenError := Error{
-		Object:  "error",
-		Type:    ErrorTypeValidation,
-		Code:    ErrorCodeSessionTokenInvalid,
-		Message: "The session token is invalid",
-	}
-
-	// Parse the string for a token
-	parser := jwt.Parser{
-		ValidMethods:         []string{jwt.SigningMethodRS256.Name},
-		SkipClaimsValidation: true,
-	}
-
-	// Parse the token
-	token, err := parser.Parse(tokenStr, s.getValidationKey)
-	if err != nil {
-		return nil, invalidTokenError
-	}
-
-	// Validate the basic session token claims
-	claims := token.Claims.(jwt.MapClaims)
-	if claims["iss"] != featherIssuer {
-		return nil, invalidTokenError
-	}
-	subject, ok := claims["sub"].(string)
-	if !ok || !strings.HasPrefix(subject, "USR_") {
-		return nil, invalidTokenError
-	}
-	audience, ok := claims["aud"].(string)
-	if !ok || !strings.HasPrefix(audience, "PRJ_") {
-		return nil, invalidTokenError
-	}
-	sessionID, ok := claims["ses"].(string)
-	if !ok || !strings.HasPrefix(sessionID, "SES_") {
-		return nil, invalidTokenError
-	}
-	cat, ok := claims["cat"].(float64)
-	if !ok {
-		return nil, invalidTokenError
-	}
-	createdAt := time.Unix(int64(cat), 0).UTC()
-
-	// Generate a session object from the token
-	session := Session{
-		ID:        sessionID,
-		Object:    "session",
-		Status:    SessionStatusActive,
-		Token:     &tokenStr,
-		UserID:    subject,
-		CreatedAt: createdAt,
-		RevokedAt: nil,
-	}
+	decoded := inspectSessionToken(tokenStr, s.getPublicKey)
+	switch decoded.Status {
+	case SessionTokenStatusValid, SessionTokenStatusNotYetValid:
+		return decoded.session(tokenStr), nil
 
-	// Check if the token is expired
-	exp, ok := claims["exp"].(float64)
-	if !ok {
-		return nil, invalidTokenError
-	}
-	if time.Now().After(time.Unix(int64(exp), 0)) {
-		return &session, Error{
+	case SessionTokenStatusExpired:
+		return decoded.session(tokenStr), Error{
 			Type:    ErrorTypeValidation,
 			Code:    ErrorCodeSessionTokenExpired,
 			Message: "The provided session token is expired",
 		}
-	}
 
-	return &session, nil
-}
-
-func (s *sessions) getValidationKey(token *jwt.Token) (interface{}, error) {
-	keyID, ok := token.Header["kid"].(string)
-	if !ok || keyID == "" {
-		return nil, errors.New("Header 'kid' not found")
+	default:
+		return nil, Error{
+			Object:  "error",
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeSessionTokenInvalid,
+			Message: "The session token is invalid",
+		}
 	}
-	return s.getPublicKey(keyID)
 }
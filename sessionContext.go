@@ -0,0 +1,16 @@
+package feather
+
+import "context"
+
+type sessionContextKey struct{}
+
+// ContextWithSession returns a copy of the context which holds the session.
+func ContextWithSession(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
+
+// SessionFromContext returns the session held by the context, if any.
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(sessionContextKey{}).(*Session)
+	return session, ok && session != nil
+}
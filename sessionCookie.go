@@ -0,0 +1,182 @@
+package feather
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSessionCookieName = "feather_session"
+
+	// Browsers limit cookies to 4096 bytes including the name and attributes,
+	// so tokens longer than this are split across several cookies.
+	maxSessionCookieValueSize = 3800
+
+	// sessionCookieChunksPrefix marks a cookie whose token is split into chunks.
+	// The value is the number of chunks (eg "chunks:3"), which are stored in
+	// cookies named "<name>.0", "<name>.1", etc.
+	sessionCookieChunksPrefix = "chunks:"
+)
+
+// SessionCookie stores session tokens in a secure, HttpOnly cookie.
+type SessionCookie struct {
+	// Name defaults to "feather_session".
+	Name   string
+	Domain string
+
+	// Path defaults to "/".
+	Path string
+
+	// Insecure allows the cookie to be sent over plain HTTP, eg in development.
+	Insecure bool
+
+	// SameSite defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+}
+
+// Set stores the session's token in the cookie. The cookie expires when the token
+// expires, or with the session if the token has no expiry. Validate rotates the
+// cookie when the token is refreshed, so the cookie's expiry is extended with it.
+func (c SessionCookie) Set(w http.ResponseWriter, session *Session) error {
+	if session == nil || session.Token == nil {
+		return Error{
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeSessionTokenInvalid,
+			Message: "The session does not include a session token",
+		}
+	}
+	token := *session.Token
+	expires := sessionCookieExpiry(session)
+
+	if len(token) <= maxSessionCookieValueSize {
+		http.SetCookie(w, c.cookie(c.name(), token, expires))
+		return nil
+	}
+	chunks := 0
+	for ; len(token) > 0; chunks++ {
+		n := maxSessionCookieValueSize
+		if len(token) < n {
+			n = len(token)
+		}
+		http.SetCookie(w, c.cookie(c.chunkName(chunks), token[:n], expires))
+		token = token[n:]
+	}
+	http.SetCookie(w, c.cookie(c.name(), sessionCookieChunksPrefix+strconv.Itoa(chunks), expires))
+	return nil
+}
+
+// Clear removes the cookie, including any chunks the request holds.
+func (c SessionCookie) Clear(w http.ResponseWriter, r *http.Request) {
+	expired := time.Unix(0, 0)
+	http.SetCookie(w, c.cookie(c.name(), "", expired))
+	for i := 0; ; i++ {
+		if _, err := r.Cookie(c.chunkName(i)); err != nil {
+			return
+		}
+		http.SetCookie(w, c.cookie(c.chunkName(i), "", expired))
+	}
+}
+
+// Token returns the session token stored in the request's cookies, if any.
+func (c SessionCookie) Token(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(c.name())
+	if err != nil || cookie.Value == "" {
+		return "", false
+	}
+	if !strings.HasPrefix(cookie.Value, sessionCookieChunksPrefix) {
+		return cookie.Value, true
+	}
+	chunks, err := strconv.Atoi(strings.TrimPrefix(cookie.Value, sessionCookieChunksPrefix))
+	if err != nil || chunks <= 0 {
+		return "", false
+	}
+	var token strings.Builder
+	for i := 0; i < chunks; i++ {
+		chunk, err := r.Cookie(c.chunkName(i))
+		if err != nil {
+			return "", false
+		}
+		token.WriteString(chunk.Value)
+	}
+	return token.String(), true
+}
+
+// Validate validates the session token stored in the request's cookies.
+// If the token was refreshed, the cookie is rotated to hold the new token.
+// If the session is no longer active, the cookie is cleared and an Error with code
+// ErrorCodeSessionRevoked, ErrorCodeSessionExpired or ErrorCodeSessionInactive is returned.
+func (c SessionCookie) Validate(w http.ResponseWriter, r *http.Request, sessions Sessions) (*Session, error) {
+	token, ok := c.Token(r)
+	if !ok {
+		return nil, Error{
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeSessionTokenInvalid,
+			Message: "The request does not have a session cookie",
+		}
+	}
+	session, err := sessions.Validate(SessionsValidateParams{
+		SessionToken: &token,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != SessionStatusActive {
+		c.Clear(w, r)
+		code := ErrorCodeSessionInactive
+		switch session.Status {
+		case SessionStatusRevoked:
+			code = ErrorCodeSessionRevoked
+		case SessionStatusExpired:
+			code = ErrorCodeSessionExpired
+		}
+		return nil, Error{
+			Type:    ErrorTypeValidation,
+			Code:    code,
+			Message: fmt.Sprintf("The session is %v", session.Status),
+		}
+	}
+	if session.Token != nil && *session.Token != token {
+		if err := c.Set(w, session); err != nil {
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+func (c SessionCookie) cookie(name string, value string, expires time.Time) *http.Cookie {
+	sameSite := c.SameSite
+	if sameSite == 0 {
+		sameSite = http.SameSiteLaxMode
+	}
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Domain:   c.Domain,
+		Path:     stringOrDefault(c.Path, "/"),
+		Expires:  expires,
+		Secure:   !c.Insecure,
+		HttpOnly: true,
+		SameSite: sameSite,
+	}
+}
+
+func (c SessionCookie) name() string {
+	return stringOrDefault(c.Name, defaultSessionCookieName)
+}
+
+func (c SessionCookie) chunkName(i int) string {
+	return c.name() + "." + strconv.Itoa(i)
+}
+
+func sessionCookieExpiry(session *Session) time.Time {
+	if exp, ok := DecodeSessionToken(*session.Token).Claims["exp"].(float64); ok {
+		return time.Unix(int64(exp), 0).UTC()
+	}
+	if session.ExpiresAt != nil {
+		return *session.ExpiresAt
+	}
+	return time.Time{}
+}
@@ -0,0 +1,183 @@
+package feather
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"strings"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// SessionTokenStatus represents the outcome of inspecting a session token.
+type SessionTokenStatus string
+
+const (
+	// The session token passed every check.
+	SessionTokenStatusValid = "valid"
+
+	// The session token could not be decoded as a JWT.
+	SessionTokenStatusMalformed = "malformed"
+
+	// The session token was not signed with RS256.
+	SessionTokenStatusInvalidAlgorithm = "invalid_algorithm"
+
+	// The session token has no key ID, or no public key is known for its key ID.
+	SessionTokenStatusUnknownKeyID = "unknown_key_id"
+
+	// The session token signature did not match its contents.
+	SessionTokenStatusInvalidSignature = "invalid_signature"
+
+	// The session token was not issued by Feather.
+	SessionTokenStatusInvalidIssuer = "invalid_issuer"
+
+	// The session token subject is not a Feather user ID.
+	SessionTokenStatusInvalidSubject = "invalid_subject"
+
+	// The session token audience is not a Feather project ID.
+	SessionTokenStatusInvalidAudience = "invalid_audience"
+
+	// The session token does not reference a Feather session ID.
+	SessionTokenStatusInvalidSessionID = "invalid_session_id"
+
+	// The session token is missing its created at or expires at timestamp.
+	SessionTokenStatusInvalidTimestamp = "invalid_timestamp"
+
+	// The session token is not valid yet. This is only reported when inspecting
+	// tokens; Sessions.Validate does not check the not before time.
+	SessionTokenStatusNotYetValid = "not_yet_valid"
+
+	// The session token has expired. Expired tokens may still be refreshed
+	// by Sessions.Validate if the session itself is active.
+	SessionTokenStatusExpired = "expired"
+)
+
+// DecodedSessionToken is a session token decoded for inspection.
+type DecodedSessionToken struct {
+	Header map[string]interface{} `json:"header"`
+	Claims map[string]interface{} `json:"claims"`
+
+	// Status is the result of the first check the token failed, or
+	// SessionTokenStatusValid if it passed every check.
+	Status SessionTokenStatus `json:"status"`
+
+	// Explanation describes exactly which check failed.
+	Explanation string `json:"explanation"`
+
+	// SignatureVerified reports whether the signature was checked against a public key.
+	SignatureVerified bool `json:"signature_verified"`
+}
+
+// DecodeSessionToken decodes a session token and runs the same checks used by
+// Sessions.Validate without making any network calls. The signature is not verified;
+// use DecodeSessionTokenWithKeys to also verify the signature.
+func DecodeSessionToken(token string) *DecodedSessionToken {
+	return inspectSessionToken(token, nil)
+}
+
+// DecodeSessionTokenWithKeys is like DecodeSessionToken but also verifies the token
+// signature with the public key matching the token's key ID.
+func DecodeSessionTokenWithKeys(token string, keys map[string]*rsa.PublicKey) *DecodedSessionToken {
+	return inspectSessionToken(token, func(keyID string) (*rsa.PublicKey, error) {
+		publicKey, ok := keys[keyID]
+		if !ok {
+			return nil, fmt.Errorf("Public key %v not found", keyID)
+		}
+		return publicKey, nil
+	})
+}
+
+// inspectSessionToken decodes the token and checks it in the same order as
+// parseSessionToken. If getKey is nil, the signature is not verified.
+func inspectSessionToken(tokenStr string, getKey func(keyID string) (*rsa.PublicKey, error)) *DecodedSessionToken {
+	decoded := DecodedSessionToken{}
+	fail := func(status SessionTokenStatus, format string, a ...interface{}) *DecodedSessionToken {
+		decoded.Status = status
+		decoded.Explanation = fmt.Sprintf(format, a...)
+		return &decoded
+	}
+
+	// Decode the token without verifying it
+	parser := jwt.Parser{
+		ValidMethods:         []string{jwt.SigningMethodRS256.Name},
+		SkipClaimsValidation: true,
+	}
+	token, _, err := parser.ParseUnverified(tokenStr, jwt.MapClaims{})
+	if err != nil {
+		return fail(SessionTokenStatusMalformed, "The token could not be decoded: %v", err)
+	}
+	claims := token.Claims.(jwt.MapClaims)
+	decoded.Header = token.Header
+	decoded.Claims = claims
+
+	// Check the header
+	if alg := token.Header["alg"]; alg != jwt.SigningMethodRS256.Name {
+		return fail(SessionTokenStatusInvalidAlgorithm, "The token is signed with %v but must be signed with %v", alg, jwt.SigningMethodRS256.Name)
+	}
+	keyID, ok := token.Header["kid"].(string)
+	if !ok || keyID == "" {
+		return fail(SessionTokenStatusUnknownKeyID, "The token header has no key ID ('kid')")
+	}
+
+	// Verify the signature
+	if getKey != nil {
+		_, err := parser.Parse(tokenStr, func(*jwt.Token) (interface{}, error) {
+			return getKey(keyID)
+		})
+		if err != nil {
+			if verr, ok := err.(*jwt.ValidationError); ok && verr.Errors&jwt.ValidationErrorUnverifiable != 0 {
+				return fail(SessionTokenStatusUnknownKeyID, "No public key could be found for key ID %v: %v", keyID, verr.Inner)
+			}
+			return fail(SessionTokenStatusInvalidSignature, "The token signature is invalid: %v", err)
+		}
+		decoded.SignatureVerified = true
+	}
+
+	// Check the session token claims
+	if claims["iss"] != featherIssuer {
+		return fail(SessionTokenStatusInvalidIssuer, "The token issuer ('iss') is %v but must be %v", claims["iss"], featherIssuer)
+	}
+	if subject, ok := claims["sub"].(string); !ok || !strings.HasPrefix(subject, "USR_") {
+		return fail(SessionTokenStatusInvalidSubject, "The token subject ('sub') is %v but must be a user ID beginning with USR_", claims["sub"])
+	}
+	if audience, ok := claims["aud"].(string); !ok || !strings.HasPrefix(audience, "PRJ_") {
+		return fail(SessionTokenStatusInvalidAudience, "The token audience ('aud') is %v but must be a project ID beginning with PRJ_", claims["aud"])
+	}
+	if sessionID, ok := claims["ses"].(string); !ok || !strings.HasPrefix(sessionID, "SES_") {
+		return fail(SessionTokenStatusInvalidSessionID, "The token session ('ses') is %v but must be a session ID beginning with SES_", claims["ses"])
+	}
+	if _, ok := claims["cat"].(float64); !ok {
+		return fail(SessionTokenStatusInvalidTimestamp, "The token created at timestamp ('cat') is %v but must be a number", claims["cat"])
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fail(SessionTokenStatusInvalidTimestamp, "The token expires at timestamp ('exp') is %v but must be a number", claims["exp"])
+	}
+
+	// Check the token's validity period. The not before time ('nbf') is checked
+	// last as it is only reported here; parseSessionToken does not enforce it.
+	now := time.Now()
+	if now.After(time.Unix(int64(exp), 0)) {
+		return fail(SessionTokenStatusExpired, "The token expired at %v", time.Unix(int64(exp), 0).UTC().Format(time.RFC3339))
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Before(time.Unix(int64(nbf), 0)) {
+		return fail(SessionTokenStatusNotYetValid, "The token is not valid until %v", time.Unix(int64(nbf), 0).UTC().Format(time.RFC3339))
+	}
+
+	decoded.Status = SessionTokenStatusValid
+	decoded.Explanation = "The token passed every check"
+	return &decoded
+}
+
+// session generates a session object from the token claims.
+func (d *DecodedSessionToken) session(tokenStr string) *Session {
+	return &Session{
+		ID:        d.Claims["ses"].(string),
+		Object:    "session",
+		Status:    SessionStatusActive,
+		Token:     &tokenStr,
+		UserID:    d.Claims["sub"].(string),
+		CreatedAt: time.Unix(int64(d.Claims["cat"].(float64)), 0).UTC(),
+		RevokedAt: nil,
+	}
+}
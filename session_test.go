@@ -0,0 +1,40 @@
+package feather
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"sync"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSessionToken_NotBefore(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss": featherIssuer,
+		"sub": "USR_foo",
+		"aud": "PRJ_foo",
+		"ses": "SES_foo",
+		"cat": now.Unix(),
+		"nbf": now.Add(time.Minute).Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "0"
+	tokenStr, err := token.SignedString(key)
+	assert.Nil(t, err)
+
+	// Inspection reports the not before time, but validation does not enforce it
+	assert.Equal(t, SessionTokenStatus(SessionTokenStatusNotYetValid), DecodeSessionToken(tokenStr).Status)
+	s := sessions{
+		cachedPublicKeysMu: &sync.RWMutex{},
+		cachedPublicKeys:   map[string]*rsa.PublicKey{"0": &key.PublicKey},
+	}
+	session, err := s.parseSessionToken(tokenStr)
+	assert.Nil(t, err)
+	assert.Equal(t, "SES_foo", session.ID)
+}
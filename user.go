@@ -12,6 +12,7 @@ type User struct {
 	ID              string            `json:"id"`
 	Object          string            `json:"object"`
 	Email           *string           `json:"email"`
+	Phone           *string           `json:"phone"`
 	Username        *string           `json:"username"`
 	IsAnonymous     bool              `json:"is_anonymous"`
 	IsEmailVerified bool              `json:"is_email_verified"`
@@ -20,6 +21,25 @@ type User struct {
 	UpdatedAt       time.Time         `json:"updated_at"`
 	FirstActiveAt   *time.Time        `json:"first_active_at"`
 	LastActiveAt    *time.Time        `json:"last_active_at"`
+	Identities      []*Identity       `json:"identities"`
+
+	// ETag identifies this version of the user. Pass it as UsersUpdateParams.IfMatch
+	// to only apply an update if the user has not changed since it was read.
+	ETag string `json:"etag,omitempty"`
+}
+
+func (u *User) setETag(etag string) {
+	u.ETag = etag
+}
+
+// Identity is an external identity provider account linked to a Feather user.
+// https://feather.id/docs/reference/api#identityObject
+type Identity struct {
+	Object    string           `json:"object"`
+	Provider  IdentityProvider `json:"provider"`
+	Subject   string           `json:"subject"`
+	Email     *string          `json:"email"`
+	CreatedAt time.Time        `json:"created_at"`
 }
 
 // UserList is a list of Feather user objects.
@@ -36,16 +56,53 @@ type Users interface {
 	Retrieve(id string) (*User, error)
 	Update(id string, params UsersUpdateParams) (*User, error)
 	UpdatePassword(id string, params UsersUpdatePasswordParams) (*User, error)
+	Import(params UsersImportParams) (*UserImportResult, error)
+	PatchMetadata(id string, params UsersPatchMetadataParams) (*User, error)
+}
+
+// UserDeleter is implemented by the Users resource of a Client created with New.
+// It is separate from Users so existing implementations of Users remain valid;
+// use a type assertion, eg client.Users.(feather.UserDeleter), to delete users.
+type UserDeleter interface {
+	Delete(id string) (*User, error)
 }
 
 type users struct {
 	gateway gateway
+	cache   *clientUserCache
 }
 
-func newUsersResource(g gateway) users {
+func newUsersResource(g gateway, cache *clientUserCache) users {
 	return users{
 		gateway: g,
+		cache:   cache,
+	}
+}
+
+// invalidate removes the user from the cache after this client modifies it.
+func (u users) invalidate(id string) {
+	u.cache.invalidate(id)
+}
+
+// invalidateIfStale removes the user from the cache if err shows that the
+// user was modified elsewhere, so the cached copy is out of date.
+func (u users) invalidateIfStale(id string, err error) {
+	ferr, ok := err.(Error)
+	if ok && (ferr.Code == ErrorCodePreconditionFailed || ferr.Code == ErrorCodeMetadataConflict) {
+		u.invalidate(id)
+	}
+}
+
+// Delete a user.
+// https://feather.id/docs/reference/api#deleteUser
+func (u users) Delete(id string) (*User, error) {
+	var user User
+	path := strings.Join([]string{pathUsers, id}, "/")
+	if err := u.gateway.sendRequest(http.MethodDelete, path, nil, &user); err != nil {
+		return nil, err
 	}
+	u.invalidate(id)
+	return &user, nil
 }
 
 // List a project's users.
@@ -66,11 +123,16 @@ type UsersListParams struct {
 // Retrieve a user.
 // https://feather.id/docs/reference/api#retrieveUser
 func (u users) Retrieve(id string) (*User, error) {
+	if user, ok := u.cache.get(id); ok {
+		return user, nil
+	}
+	generation := u.cache.currentGeneration()
 	var user User
 	path := strings.Join([]string{pathUsers, id}, "/")
 	if err := u.gateway.sendRequest(http.MethodGet, path, nil, &user); err != nil {
 		return nil, err
 	}
+	u.cache.set(id, &user, generation)
 	return &user, nil
 }
 
@@ -80,16 +142,44 @@ func (u users) Update(id string, params UsersUpdateParams) (*User, error) {
 	var user User
 	path := strings.Join([]string{pathUsers, id}, "/")
 	if err := u.gateway.sendRequest(http.MethodPost, path, params, &user); err != nil {
+		u.invalidateIfStale(id, err)
 		return nil, err
 	}
+	u.invalidate(id)
 	return &user, nil
 }
 
 // UsersUpdateParams ...
+//
+// IfMatch and IfUnmodifiedSince are preconditions sent as the If-Match and
+// If-Unmodified-Since headers. If the user has changed since it was read, the update
+// is rejected with ErrorCodePreconditionFailed and the caller should re-read the user and retry.
+// HTTP dates have one second precision, so IfUnmodifiedSince is truncated to whole seconds;
+// use IfMatch to also detect changes made later in the same second.
 type UsersUpdateParams struct {
-	Email    *string            `json:"email"`
-	Username *string            `json:"username"`
-	Metadata *map[string]string `json:"metadata"`
+	Email             *string            `json:"email"`
+	Phone             *string            `json:"phone"`
+	Username          *string            `json:"username"`
+	Metadata          *map[string]string `json:"metadata"`
+	IfMatch           *string            `json:"-"`
+	IfUnmodifiedSince *time.Time         `json:"-"`
+}
+
+func (p UsersUpdateParams) requestHeaders() http.Header {
+	header := http.Header{}
+	if p.IfMatch != nil {
+		header.Set("If-Match", *p.IfMatch)
+	}
+	if p.IfUnmodifiedSince != nil {
+		header.Set("If-Unmodified-Since", formatIfUnmodifiedSince(*p.IfUnmodifiedSince))
+	}
+	return header
+}
+
+// formatIfUnmodifiedSince formats t as an HTTP date. Fractional seconds are truncated,
+// since rounding up would let a change made later in the same second pass the precondition.
+func formatIfUnmodifiedSince(t time.Time) string {
+	return t.UTC().Format(http.TimeFormat)
 }
 
 // Update a user password.
@@ -100,6 +190,7 @@ func (u users) UpdatePassword(id string, params UsersUpdatePasswordParams) (*Use
 	if err := u.gateway.sendRequest(http.MethodPost, path, params, &user); err != nil {
 		return nil, err
 	}
+	u.invalidate(id)
 	return &user, nil
 }
 
@@ -0,0 +1,223 @@
+package feather
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// UserCache is a cache of users used by Users.Retrieve.
+// Entries are invalidated when the same client updates or deletes the user;
+// changes made elsewhere are only observed once an entry expires.
+type UserCache interface {
+	Get(id string) (*User, bool)
+	Set(id string, user *User)
+	Delete(id string)
+}
+
+// CacheStats are the counters of a LRUUserCache.
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+}
+
+// LRUUserCache is an in-memory UserCache which evicts the least recently used
+// user once it reaches its capacity, and expires users after a TTL.
+type LRUUserCache struct {
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	stats   CacheStats
+}
+
+type lruUserCacheEntry struct {
+	id        string
+	user      *User
+	expiresAt time.Time
+}
+
+// NewLRUUserCache creates a new in-memory cache holding at most capacity users,
+// each for at most ttl. A capacity of zero or less means the cache is unbounded,
+// and a ttl of zero means users never expire.
+func NewLRUUserCache(capacity int, ttl time.Duration) *LRUUserCache {
+	return &LRUUserCache{
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// Get returns the cached user, if present and not expired.
+func (c *LRUUserCache) Get(id string) (*User, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[id]
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	entry := elem.Value.(*lruUserCacheEntry)
+	if c.ttl > 0 && c.now().After(entry.expiresAt) {
+		c.remove(elem)
+		c.stats.Misses++
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	c.stats.Hits++
+	return entry.user, true
+}
+
+// Set caches the user, evicting the least recently used user if the cache is full.
+func (c *LRUUserCache) Set(id string, user *User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[id]; ok {
+		c.remove(elem)
+	}
+	c.entries[id] = c.order.PushFront(&lruUserCacheEntry{
+		id:        id,
+		user:      user,
+		expiresAt: c.now().Add(c.ttl),
+	})
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// Delete removes the user from the cache.
+func (c *LRUUserCache) Delete(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[id]; ok {
+		c.remove(elem)
+	}
+}
+
+// Stats returns the cache's counters.
+func (c *LRUUserCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Size = c.order.Len()
+	return stats
+}
+
+func (c *LRUUserCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*lruUserCacheEntry).id)
+}
+
+// clientUserCache wraps the Config.UserCache of a client. It is shared by the client's
+// resources so any of them can invalidate a user.
+type clientUserCache struct {
+	cache UserCache
+
+	// generation is incremented by every invalidation, so a user fetched while
+	// an invalidation happened is not written back to the cache.
+	mu         sync.Mutex
+	generation uint64
+}
+
+func newClientUserCache(cache UserCache) *clientUserCache {
+	if cache == nil {
+		return nil
+	}
+	return &clientUserCache{cache: cache}
+}
+
+// get returns a copy of the cached user, if any. It is safe to call on a nil cache.
+func (c *clientUserCache) get(id string) (*User, bool) {
+	if c == nil {
+		return nil, false
+	}
+	user, ok := c.cache.Get(id)
+	if !ok {
+		return nil, false
+	}
+	return copyUser(user), true
+}
+
+// currentGeneration returns the generation to pass to set once the user is fetched.
+func (c *clientUserCache) currentGeneration() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// set caches a copy of the user unless there was an invalidation since generation.
+func (c *clientUserCache) set(id string, user *User, generation uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.cache.Set(id, copyUser(user))
+	}
+}
+
+// invalidate removes the user from the cache after this client modifies it.
+func (c *clientUserCache) invalidate(id string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.cache.Delete(id)
+}
+
+// copyUser returns a deep copy of the user so cached users cannot be modified by callers.
+func copyUser(user *User) *User {
+	cp := *user
+	cp.Email = copyString(user.Email)
+	cp.Phone = copyString(user.Phone)
+	cp.Username = copyString(user.Username)
+	cp.FirstActiveAt = copyTime(user.FirstActiveAt)
+	cp.LastActiveAt = copyTime(user.LastActiveAt)
+	if user.Metadata != nil {
+		cp.Metadata = make(map[string]string, len(user.Metadata))
+		for k, v := range user.Metadata {
+			cp.Metadata[k] = v
+		}
+	}
+	if user.Identities != nil {
+		cp.Identities = make([]*Identity, len(user.Identities))
+		for i, identity := range user.Identities {
+			if identity != nil {
+				identityCopy := *identity
+				identityCopy.Email = copyString(identity.Email)
+				cp.Identities[i] = &identityCopy
+			}
+		}
+	}
+	return &cp
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	cp := *s
+	return &cp
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	cp := *t
+	return &cp
+}
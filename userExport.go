@@ -0,0 +1,273 @@
+package feather
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultExportPageSize = 100
+
+// ExportFormat represents the file format produced by an export.
+type ExportFormat string
+
+const (
+	// One JSON object per line.
+	ExportFormatJSONLines = "jsonl"
+
+	// Comma-separated values with a header row.
+	ExportFormatCSV = "csv"
+)
+
+// DefaultUserExportFields are the fields exported when none are selected.
+var DefaultUserExportFields = []string{
+	"id",
+	"email",
+	"username",
+	"phone",
+	"is_anonymous",
+	"is_email_verified",
+	"created_at",
+	"updated_at",
+	"first_active_at",
+	"last_active_at",
+}
+
+// UserExporter walks every page of a project's users and writes them to an io.Writer.
+//
+// Fields may be any of the user's JSON field names, "metadata.<key>" to flatten a
+// metadata key into its own field, "metadata" for the whole metadata map, or
+// "sessions", "session_count" and "active_session_count". Selecting a session field
+// lists each user's sessions, which requires Sessions to be set. Session tokens are
+// never exported. In CSV, the metadata and sessions fields are written as JSON.
+// JSON Lines objects hold the fields in the order they are selected.
+type UserExporter struct {
+	Users    Users
+	Sessions Sessions
+	Format   ExportFormat
+	Fields   []string
+
+	// PageSize is the number of users requested per page. Defaults to 100.
+	PageSize uint32
+
+	// Progress is called after each page is written with the number of users
+	// exported so far and the total number of users in the project.
+	Progress func(exported int, total int)
+}
+
+// Export writes every user to w and returns the number of users written.
+func (e UserExporter) Export(w io.Writer) (int, error) {
+	fields := e.Fields
+	if len(fields) == 0 {
+		fields = DefaultUserExportFields
+	}
+	includeSessions := false
+	for _, field := range fields {
+		if !isUserExportField(field) {
+			return 0, fmt.Errorf("Unknown export field %q", field)
+		}
+		switch field {
+		case "sessions", "session_count", "active_session_count":
+			includeSessions = true
+		}
+	}
+	if includeSessions && e.Sessions == nil {
+		return 0, errors.New("Exporting session fields requires the Sessions resource")
+	}
+
+	var write func(values []interface{}) error
+	switch e.Format {
+	case ExportFormatJSONLines, "":
+		write = func(values []interface{}) error {
+			var line bytes.Buffer
+			line.WriteByte('{')
+			for i, field := range fields {
+				if i > 0 {
+					line.WriteByte(',')
+				}
+				key, _ := json.Marshal(field)
+				value, err := json.Marshal(values[i])
+				if err != nil {
+					return err
+				}
+				line.Write(key)
+				line.WriteByte(':')
+				line.Write(value)
+			}
+			line.WriteString("}\n")
+			_, err := w.Write(line.Bytes())
+			return err
+		}
+	case ExportFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(fields); err != nil {
+			return 0, err
+		}
+		write = func(values []interface{}) error {
+			row := make([]string, len(values))
+			for i, v := range values {
+				row[i] = formatExportCSVValue(v)
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+	default:
+		return 0, fmt.Errorf("Unknown export format %q", e.Format)
+	}
+
+	pageSize := e.PageSize
+	if pageSize == 0 {
+		pageSize = defaultExportPageSize
+	}
+	exported := 0
+	params := UsersListParams{}
+	params.Limit = &pageSize
+	for {
+		userList, err := e.Users.List(params)
+		if err != nil {
+			return exported, err
+		}
+		for _, user := range userList.Data {
+			var sessions []*Session
+			if includeSessions {
+				if sessions, err = e.listSessions(user.ID, pageSize); err != nil {
+					return exported, err
+				}
+			}
+			values := make([]interface{}, len(fields))
+			for i, field := range fields {
+				values[i] = userExportValue(user, sessions, field)
+			}
+			if err := write(values); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+		if e.Progress != nil {
+			e.Progress(exported, int(userList.TotalCount))
+		}
+		if len(userList.Data) < int(pageSize) {
+			return exported, nil
+		}
+		params.StartingAfter = String(userList.Data[len(userList.Data)-1].ID)
+	}
+}
+
+func (e UserExporter) listSessions(userID string, pageSize uint32) ([]*Session, error) {
+	var sessions []*Session
+	params := SessionsListParams{UserID: String(userID)}
+	params.Limit = &pageSize
+	for {
+		sessionList, err := e.Sessions.List(params)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sessionList.Data...)
+		if len(sessionList.Data) < int(pageSize) {
+			return sessions, nil
+		}
+		params.StartingAfter = String(sessionList.Data[len(sessionList.Data)-1].ID)
+	}
+}
+
+func isUserExportField(field string) bool {
+	switch field {
+	case "id", "object", "email", "username", "phone", "is_anonymous", "is_email_verified",
+		"metadata", "created_at", "updated_at", "first_active_at", "last_active_at",
+		"sessions", "session_count", "active_session_count":
+		return true
+	}
+	return strings.HasPrefix(field, "metadata.") && len(field) > len("metadata.")
+}
+
+func userExportValue(user *User, sessions []*Session, field string) interface{} {
+	optionalTime := func(t *time.Time) interface{} {
+		if t == nil {
+			return nil
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	optionalString := func(s *string) interface{} {
+		if s == nil {
+			return nil
+		}
+		return *s
+	}
+	switch field {
+	case "id":
+		return user.ID
+	case "object":
+		return user.Object
+	case "email":
+		return optionalString(user.Email)
+	case "username":
+		return optionalString(user.Username)
+	case "phone":
+		return optionalString(user.Phone)
+	case "is_anonymous":
+		return user.IsAnonymous
+	case "is_email_verified":
+		return user.IsEmailVerified
+	case "metadata":
+		return user.Metadata
+	case "created_at":
+		return optionalTime(&user.CreatedAt)
+	case "updated_at":
+		return optionalTime(&user.UpdatedAt)
+	case "first_active_at":
+		return optionalTime(user.FirstActiveAt)
+	case "last_active_at":
+		return optionalTime(user.LastActiveAt)
+	case "sessions":
+		exported := make([]userExportSession, len(sessions))
+		for i, session := range sessions {
+			exported[i] = userExportSession{Session: session}
+		}
+		return exported
+	case "session_count":
+		return len(sessions)
+	case "active_session_count":
+		count := 0
+		for _, session := range sessions {
+			if session.Status == SessionStatusActive {
+				count++
+			}
+		}
+		return count
+	}
+	if v, ok := user.Metadata[strings.TrimPrefix(field, "metadata.")]; ok {
+		return v
+	}
+	return nil
+}
+
+// userExportSession hides the session's token, which grants access to the user's account.
+type userExportSession struct {
+	*Session
+	Token *string `json:"token,omitempty"`
+}
+
+func formatExportCSVValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		bytes, _ := json.Marshal(v)
+		return string(bytes)
+	}
+}
@@ -0,0 +1,386 @@
+package feather
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultUserImportBatchSize = 100
+
+// PasswordHashAlgorithm represents the algorithm used to hash an imported password.
+type PasswordHashAlgorithm string
+
+const (
+	// A bcrypt hash in modular crypt format (eg $2a$10$...).
+	PasswordHashAlgorithmBcrypt = "bcrypt"
+
+	// A scrypt hash. The salt and cost parameters are provided in PasswordHashParams.
+	PasswordHashAlgorithmScrypt = "scrypt"
+
+	// An Argon2 hash in PHC string format (eg $argon2id$v=19$...).
+	PasswordHashAlgorithmArgon2 = "argon2"
+
+	// A PBKDF2 hash. The salt, digest and iterations are provided in PasswordHashParams.
+	PasswordHashAlgorithmPBKDF2 = "pbkdf2"
+)
+
+// UserImportRecord is a single user to be imported from another identity provider.
+type UserImportRecord struct {
+	Email                 *string            `json:"email"`
+	Username              *string            `json:"username"`
+	Phone                 *string            `json:"phone"`
+	IsEmailVerified       *bool              `json:"is_email_verified"`
+	Metadata              *map[string]string `json:"metadata"`
+	PasswordHash          *string            `json:"password_hash"`
+	PasswordHashAlgorithm *string            `json:"password_hash_algorithm"`
+	PasswordHashParams    *map[string]string `json:"password_hash_params"`
+}
+
+func (r UserImportRecord) validate() error {
+	invalid := func(message string) error {
+		return Error{
+			Type:    ErrorTypeValidation,
+			Code:    ErrorCodeParameterInvalid,
+			Message: message,
+		}
+	}
+	if r.Email == nil && r.Username == nil && r.Phone == nil {
+		return invalid("An email, username or phone number is required")
+	}
+	if r.PasswordHash == nil {
+		if r.PasswordHashAlgorithm != nil {
+			return invalid("A password hash algorithm was provided without a password hash")
+		}
+		if r.PasswordHashParams != nil {
+			return invalid("Password hash params were provided without a password hash")
+		}
+		return nil
+	}
+	if r.PasswordHashAlgorithm == nil {
+		return invalid("A password hash was provided without a password hash algorithm")
+	}
+	switch *r.PasswordHashAlgorithm {
+	case PasswordHashAlgorithmBcrypt, PasswordHashAlgorithmScrypt, PasswordHashAlgorithmArgon2, PasswordHashAlgorithmPBKDF2:
+		return nil
+	default:
+		return invalid(fmt.Sprintf("The password hash algorithm %v is not supported", *r.PasswordHashAlgorithm))
+	}
+}
+
+// UserImportResult is the result of importing a batch of users.
+// https://feather.id/docs/reference/api#importUsers
+type UserImportResult struct {
+	Object string            `json:"object"`
+	Data   []*User           `json:"data"`
+	Errors []UserImportError `json:"errors"`
+}
+
+// UserImportError describes why a record in an imported batch was rejected.
+type UserImportError struct {
+	Index   int       `json:"index"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// Import a batch of users.
+// Records which are rejected, either by the client's validation or by the Feather API,
+// are listed in the result's errors by their index in params; the remaining records are imported.
+// https://feather.id/docs/reference/api#importUsers
+func (u users) Import(params UsersImportParams) (*UserImportResult, error) {
+	var rejected []UserImportError
+	var valid []UserImportRecord
+	var indexes []int
+	for i, record := range params.Users {
+		if err := record.validate(); err != nil {
+			ferr := err.(Error)
+			rejected = append(rejected, UserImportError{Index: i, Code: ferr.Code, Message: ferr.Message})
+			continue
+		}
+		valid = append(valid, record)
+		indexes = append(indexes, i)
+	}
+
+	result := UserImportResult{Data: []*User{}}
+	if len(valid) > 0 {
+		if err := u.gateway.sendRequest(http.MethodPost, pathUsersImport, UsersImportParams{Users: valid}, &result); err != nil {
+			return nil, err
+		}
+		// Map the API's indexes in the sent records back to indexes in params
+		for i, ierr := range result.Errors {
+			if ierr.Index >= 0 && ierr.Index < len(indexes) {
+				result.Errors[i].Index = indexes[ierr.Index]
+			}
+		}
+	}
+	result.Errors = append(result.Errors, rejected...)
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Index < result.Errors[j].Index
+	})
+	return &result, nil
+}
+
+// UsersImportParams ...
+type UsersImportParams struct {
+	Users []UserImportRecord `json:"users"`
+}
+
+// UserImportRecordError is returned by a UserImportReader for a record which
+// could not be read. Reading may continue with the next record.
+type UserImportRecordError struct {
+	Line int
+	Err  error
+}
+
+func (e UserImportRecordError) Error() string {
+	return fmt.Sprintf("line %v: %v", e.Line, e.Err)
+}
+
+// UserImportReader reads user import records from a source file.
+// Read returns io.EOF once every record has been read.
+type UserImportReader interface {
+	Read() (record *UserImportRecord, line int, err error)
+}
+
+// UserImportCSVReader reads user import records from CSV with a header row.
+//
+// Recognized columns are email, username, phone, is_email_verified, password_hash,
+// password_hash_algorithm, metadata.<key> and password_hash_params.<key>. Columns
+// with other names may be mapped onto these with Columns, or ignored by mapping them to "-".
+type UserImportCSVReader struct {
+	Columns map[string]string
+
+	reader *csv.Reader
+	header []string
+	line   int
+}
+
+// NewUserImportCSVReader creates a new CSV reader.
+func NewUserImportCSVReader(r io.Reader) *UserImportCSVReader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	return &UserImportCSVReader{
+		Columns: map[string]string{},
+		reader:  reader,
+	}
+}
+
+// Read reads the next record.
+func (c *UserImportCSVReader) Read() (*UserImportRecord, int, error) {
+	if c.header == nil {
+		row, err := c.reader.Read()
+		if err != nil {
+			return nil, 0, err
+		}
+		c.line++
+		for _, name := range row {
+			column := strings.TrimSpace(name)
+			if mapped, ok := c.Columns[column]; ok {
+				column = mapped
+			}
+			if !isUserImportColumn(column) {
+				return nil, c.line, fmt.Errorf("Unknown column %q", name)
+			}
+			c.header = append(c.header, column)
+		}
+	}
+
+	row, err := c.reader.Read()
+	if err == io.EOF {
+		return nil, 0, err
+	}
+	c.line++
+	if err != nil {
+		return nil, c.line, UserImportRecordError{Line: c.line, Err: err}
+	}
+	if len(row) != len(c.header) {
+		return nil, c.line, UserImportRecordError{
+			Line: c.line,
+			Err:  fmt.Errorf("Expected %v fields but found %v", len(c.header), len(row)),
+		}
+	}
+
+	var record UserImportRecord
+	for i, column := range c.header {
+		value := row[i]
+		if value == "" || column == "-" {
+			continue
+		}
+		switch {
+		case column == "email":
+			record.Email = String(value)
+		case column == "username":
+			record.Username = String(value)
+		case column == "phone":
+			record.Phone = String(value)
+		case column == "is_email_verified":
+			verified, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, c.line, UserImportRecordError{
+					Line: c.line,
+					Err:  fmt.Errorf("Column is_email_verified must be a boolean but was %q", value),
+				}
+			}
+			record.IsEmailVerified = Bool(verified)
+		case column == "password_hash":
+			record.PasswordHash = String(value)
+		case column == "password_hash_algorithm":
+			record.PasswordHashAlgorithm = String(value)
+		case strings.HasPrefix(column, "metadata."):
+			if record.Metadata == nil {
+				record.Metadata = &map[string]string{}
+			}
+			(*record.Metadata)[strings.TrimPrefix(column, "metadata.")] = value
+		case strings.HasPrefix(column, "password_hash_params."):
+			if record.PasswordHashParams == nil {
+				record.PasswordHashParams = &map[string]string{}
+			}
+			(*record.PasswordHashParams)[strings.TrimPrefix(column, "password_hash_params.")] = value
+		}
+	}
+	if err := record.validate(); err != nil {
+		return nil, c.line, UserImportRecordError{Line: c.line, Err: err}
+	}
+	return &record, c.line, nil
+}
+
+func isUserImportColumn(column string) bool {
+	switch column {
+	case "-", "email", "username", "phone", "is_email_verified", "password_hash", "password_hash_algorithm":
+		return true
+	}
+	for _, prefix := range []string{"metadata.", "password_hash_params."} {
+		if strings.HasPrefix(column, prefix) && len(column) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// UserImportJSONLinesReader reads user import records from JSON Lines,
+// where each line is a JSON object with the same fields as UserImportRecord.
+type UserImportJSONLinesReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewUserImportJSONLinesReader creates a new JSON Lines reader.
+func NewUserImportJSONLinesReader(r io.Reader) *UserImportJSONLinesReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	return &UserImportJSONLinesReader{
+		scanner: scanner,
+	}
+}
+
+// Read reads the next record, skipping blank lines.
+func (j *UserImportJSONLinesReader) Read() (*UserImportRecord, int, error) {
+	for j.scanner.Scan() {
+		j.line++
+		line := bytes.TrimSpace(j.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var record UserImportRecord
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil {
+			return nil, j.line, UserImportRecordError{Line: j.line, Err: err}
+		}
+		if err := record.validate(); err != nil {
+			return nil, j.line, UserImportRecordError{Line: j.line, Err: err}
+		}
+		return &record, j.line, nil
+	}
+	if err := j.scanner.Err(); err != nil {
+		return nil, j.line, err
+	}
+	return nil, 0, io.EOF
+}
+
+// UserImporter reads records from a UserImportReader and imports them in batches.
+type UserImporter struct {
+	Users Users
+
+	// BatchSize is the number of records sent in each request. Defaults to 100.
+	BatchSize int
+}
+
+// UserImportReport summarizes an import. Errors lists every record which
+// could not be read or was rejected by the Feather API, keyed by source line.
+type UserImportReport struct {
+	Imported int
+	Errors   []UserImportRecordError
+}
+
+// Import reads and imports every record. Records which fail to read or are rejected
+// are added to the report. If a batch request fails entirely, the report so far
+// is returned along with the error.
+func (i UserImporter) Import(r UserImportReader) (*UserImportReport, error) {
+	batchSize := i.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultUserImportBatchSize
+	}
+	report := UserImportReport{}
+	var batch []UserImportRecord
+	var lines []int
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		result, err := i.Users.Import(UsersImportParams{Users: batch})
+		if err != nil {
+			return err
+		}
+		report.Imported += len(batch) - len(result.Errors)
+		for _, ierr := range result.Errors {
+			line := 0
+			if ierr.Index >= 0 && ierr.Index < len(lines) {
+				line = lines[ierr.Index]
+			}
+			report.Errors = append(report.Errors, UserImportRecordError{
+				Line: line,
+				Err: Error{
+					Type:    ErrorTypeValidation,
+					Code:    ierr.Code,
+					Message: ierr.Message,
+				},
+			})
+		}
+		batch, lines = nil, nil
+		return nil
+	}
+
+	for {
+		record, line, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if rerr, ok := err.(UserImportRecordError); ok {
+			report.Errors = append(report.Errors, rerr)
+			continue
+		}
+		if err != nil {
+			return &report, err
+		}
+		batch = append(batch, *record)
+		lines = append(lines, line)
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return &report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return &report, err
+	}
+	return &report, nil
+}
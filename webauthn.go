@@ -0,0 +1,97 @@
+package feather
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebAuthnCeremony represents the kind of WebAuthn ceremony a challenge was issued for.
+type WebAuthnCeremony string
+
+const (
+	// A new authenticator is being registered for a user.
+	WebAuthnCeremonyRegistration = "registration"
+
+	// A registered authenticator is being used to sign in.
+	WebAuthnCeremonyAssertion = "assertion"
+)
+
+// WebAuthnChallenge is the Feather WebAuthn challenge object.
+// Options should be passed as-is to navigator.credentials.create() for a
+// registration ceremony or navigator.credentials.get() for an assertion ceremony.
+// https://feather.id/docs/reference/api#webAuthnChallengeObject
+type WebAuthnChallenge struct {
+	ID        string           `json:"id"`
+	Object    string           `json:"object"`
+	Ceremony  WebAuthnCeremony `json:"ceremony"`
+	UserID    *string          `json:"user_id"`
+	Options   json.RawMessage  `json:"options"`
+	CreatedAt time.Time        `json:"created_at"`
+	ExpiresAt time.Time        `json:"expires_at"`
+}
+
+// BeginWebAuthnRegistration starts a ceremony to register a new authenticator for a user.
+// https://feather.id/docs/reference/api#beginWebAuthnRegistration
+func (c credentials) BeginWebAuthnRegistration(params CredentialsBeginWebAuthnRegistrationParams) (*WebAuthnChallenge, error) {
+	var challenge WebAuthnChallenge
+	if err := c.gateway.sendRequest(http.MethodPost, pathWebAuthnRegistrations, params, &challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// CredentialsBeginWebAuthnRegistrationParams ...
+type CredentialsBeginWebAuthnRegistrationParams struct {
+	UserID            *string `json:"user_id"`
+	AuthenticatorName *string `json:"authenticator_name"`
+}
+
+// FinishWebAuthnRegistration completes a registration ceremony with the
+// attestation response produced by the browser. If the attestation is valid,
+// the returned credential's token may be used to create a session.
+// https://feather.id/docs/reference/api#finishWebAuthnRegistration
+func (c credentials) FinishWebAuthnRegistration(challengeID string, params CredentialsFinishWebAuthnParams) (*Credential, error) {
+	var credential Credential
+	path := strings.Join([]string{pathWebAuthnRegistrations, challengeID}, "/")
+	if err := c.gateway.sendRequest(http.MethodPost, path, params, &credential); err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// BeginWebAuthnAssertion starts a ceremony to sign in with a registered authenticator.
+// The user ID may be omitted to allow discoverable credentials (passkeys).
+// https://feather.id/docs/reference/api#beginWebAuthnAssertion
+func (c credentials) BeginWebAuthnAssertion(params CredentialsBeginWebAuthnAssertionParams) (*WebAuthnChallenge, error) {
+	var challenge WebAuthnChallenge
+	if err := c.gateway.sendRequest(http.MethodPost, pathWebAuthnAssertions, params, &challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// CredentialsBeginWebAuthnAssertionParams ...
+type CredentialsBeginWebAuthnAssertionParams struct {
+	UserID *string `json:"user_id"`
+}
+
+// FinishWebAuthnAssertion completes an assertion ceremony with the
+// assertion response produced by the browser. If the assertion is valid,
+// the returned credential's token may be used to create a session.
+// https://feather.id/docs/reference/api#finishWebAuthnAssertion
+func (c credentials) FinishWebAuthnAssertion(challengeID string, params CredentialsFinishWebAuthnParams) (*Credential, error) {
+	var credential Credential
+	path := strings.Join([]string{pathWebAuthnAssertions, challengeID}, "/")
+	if err := c.gateway.sendRequest(http.MethodPost, path, params, &credential); err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// CredentialsFinishWebAuthnParams ...
+type CredentialsFinishWebAuthnParams struct {
+	// Response is the JSON-encoded PublicKeyCredential returned by the browser.
+	Response *string `json:"response"`
+}
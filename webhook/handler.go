@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const maxPayloadBytes = 1 << 20
+
+// A HandlerFunc handles a single verified webhook event.
+// Returning an error responds with a 500 status so Feather retries the delivery.
+type HandlerFunc func(event *Event) error
+
+// Handler is an http.Handler which verifies webhook requests and dispatches
+// each event to the HandlerFunc registered for its type.
+// Events without a registered HandlerFunc are acknowledged and ignored.
+// Create handlers with NewHandler; a zero Handler has no secret and rejects every request.
+type Handler struct {
+	secret string
+
+	// Tolerance is the maximum age of a signed webhook that will be accepted.
+	// If zero or negative, DefaultTolerance is used.
+	Tolerance time.Duration
+
+	mu       sync.RWMutex
+	handlers map[EventType]HandlerFunc
+}
+
+// NewHandler creates a new webhook handler which verifies requests with the endpoint's secret.
+func NewHandler(secret string) *Handler {
+	return &Handler{
+		secret:    secret,
+		Tolerance: DefaultTolerance,
+		handlers:  map[EventType]HandlerFunc{},
+	}
+}
+
+// Handle registers the HandlerFunc for the provided event type,
+// replacing any previously registered HandlerFunc.
+func (h *Handler) Handle(eventType EventType, fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.handlers == nil {
+		h.handlers = map[EventType]HandlerFunc{}
+	}
+	h.handlers[eventType] = fn
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadBytes))
+	if err != nil {
+		http.Error(w, "Failed to read the webhook payload", http.StatusBadRequest)
+		return
+	}
+	tolerance := h.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	event, err := ConstructEventWithTolerance(payload, r.Header.Get(SignatureHeader), h.secret, tolerance)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.RLock()
+	fn, ok := h.handlers[event.Type]
+	h.mu.RUnlock()
+	if ok {
+		if err := fn(event); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,196 @@
+// Package webhook verifies and dispatches webhook events sent by Feather.
+//
+// Feather signs every webhook request with the endpoint's secret. The signature is sent in
+// the Feather-Signature header as "t=<unix timestamp>,v1=<hex encoded HMAC-SHA256>", where
+// the HMAC is computed over "<timestamp>.<raw request body>".
+//
+// For more information on webhooks, please check out our docs at https://feather.id/docs/reference/api#webhooks.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/feather-id/feather-go"
+)
+
+const (
+	// SignatureHeader is the HTTP header carrying the webhook signature.
+	SignatureHeader = "Feather-Signature"
+
+	// DefaultTolerance is the maximum age of a signed webhook that will be accepted.
+	DefaultTolerance = 5 * time.Minute
+
+	signatureScheme = "v1"
+)
+
+// EventType represents the type of a webhook event.
+type EventType string
+
+const (
+	// A user was created.
+	EventTypeUserCreated = "user.created"
+
+	// A user was updated.
+	EventTypeUserUpdated = "user.updated"
+
+	// A user verified their email address.
+	EventTypeUserEmailVerified = "user.email_verified"
+
+	// A credential was created.
+	EventTypeCredentialCreated = "credential.created"
+
+	// A credential was verified.
+	EventTypeCredentialVerified = "credential.verified"
+
+	// A session was created.
+	EventTypeSessionCreated = "session.created"
+
+	// A session was upgraded.
+	EventTypeSessionUpgraded = "session.upgraded"
+
+	// A session was revoked.
+	EventTypeSessionRevoked = "session.revoked"
+)
+
+// Event is the Feather event object delivered to webhook endpoints.
+// At most one of User, Session or Credential is set, depending on the event type.
+// None are set for event types this package does not know; decode Data instead.
+// https://feather.id/docs/reference/api#eventObject
+type Event struct {
+	ID        string          `json:"id"`
+	Object    string          `json:"object"`
+	Type      EventType       `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+
+	User       *feather.User       `json:"-"`
+	Session    *feather.Session    `json:"-"`
+	Credential *feather.Credential `json:"-"`
+}
+
+// ConstructEvent verifies the signature of a webhook payload and decodes it into an Event.
+// Payloads signed more than DefaultTolerance ago are rejected to prevent replays.
+func ConstructEvent(payload []byte, signature string, secret string) (*Event, error) {
+	return ConstructEventWithTolerance(payload, signature, secret, DefaultTolerance)
+}
+
+// ConstructEventWithTolerance is like ConstructEvent but with a custom timestamp tolerance.
+// The tolerance must be positive; replay protection cannot be disabled.
+func ConstructEventWithTolerance(payload []byte, signature string, secret string, tolerance time.Duration) (*Event, error) {
+	if tolerance <= 0 {
+		return nil, feather.Error{
+			Object:  "error",
+			Type:    feather.ErrorTypeValidation,
+			Code:    feather.ErrorCodeParameterInvalid,
+			Message: "The webhook timestamp tolerance must be positive",
+		}
+	}
+	if err := verifySignature(payload, signature, secret, tolerance, time.Now()); err != nil {
+		return nil, err
+	}
+	return parseEvent(payload)
+}
+
+// ComputeSignature returns the Feather-Signature header value for the payload
+// signed with the secret at the provided time. It is primarily useful for testing.
+func ComputeSignature(payload []byte, secret string, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%v,%v=%v", timestamp, signatureScheme, computeMAC(payload, secret, timestamp))
+}
+
+func computeMAC(payload []byte, secret string, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func verifySignature(payload []byte, signature string, secret string, tolerance time.Duration, now time.Time) error {
+	invalidSignatureError := feather.Error{
+		Object:  "error",
+		Type:    feather.ErrorTypeValidation,
+		Code:    feather.ErrorCodeWebhookSignatureInvalid,
+		Message: "The webhook signature is invalid",
+	}
+
+	// Parse the header for the timestamp and signatures
+	var timestamp string
+	var macs []string
+	for _, pair := range strings.Split(signature, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case signatureScheme:
+			macs = append(macs, kv[1])
+		}
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(macs) == 0 || secret == "" {
+		return invalidSignatureError
+	}
+
+	// Check the timestamp is within the tolerance
+	age := now.Sub(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return feather.Error{
+			Object:  "error",
+			Type:    feather.ErrorTypeValidation,
+			Code:    feather.ErrorCodeWebhookTimestampExpired,
+			Message: "The webhook timestamp is outside of the tolerance zone",
+		}
+	}
+
+	// Compare against each provided signature
+	expected := []byte(computeMAC(payload, secret, timestamp))
+	for _, mac := range macs {
+		if hmac.Equal(expected, []byte(mac)) {
+			return nil
+		}
+	}
+	return invalidSignatureError
+}
+
+func parseEvent(payload []byte) (*Event, error) {
+	unparsableEventError := feather.Error{
+		Object:  "error",
+		Type:    feather.ErrorTypeValidation,
+		Code:    feather.ErrorCodeParsingFailed,
+		Message: "The webhook payload could not be parsed",
+	}
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, unparsableEventError
+	}
+
+	// Decode the data into the object matching the event type
+	var into interface{}
+	switch strings.SplitN(string(event.Type), ".", 2)[0] {
+	case "user":
+		event.User = &feather.User{}
+		into = event.User
+	case "session":
+		event.Session = &feather.Session{}
+		into = event.Session
+	case "credential":
+		event.Credential = &feather.Credential{}
+		into = event.Credential
+	default:
+		return &event, nil
+	}
+	if err := json.Unmarshal(event.Data, into); err != nil {
+		return nil, unparsableEventError
+	}
+	return &event, nil
+}
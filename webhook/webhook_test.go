@@ -0,0 +1,196 @@
+package webhook_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/feather-id/feather-go"
+	"github.com/feather-id/feather-go/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleSecret = "whsec_foo"
+
+var sampleUserCreatedPayload = []byte(`{
+	"id": "EVT_foo",
+	"object": "event",
+	"type": "user.created",
+	"created_at": "2020-01-01T01:01:01Z",
+	"data": {
+		"id": "USR_foo",
+		"object": "user",
+		"email": "foo@bar.com",
+		"is_anonymous": false,
+		"is_email_verified": false,
+		"metadata": {},
+		"created_at": "2020-01-01T01:01:01Z",
+		"updated_at": "2020-01-01T01:01:01Z"
+	}
+}`)
+
+var sampleSessionRevokedPayload = []byte(`{
+	"id": "EVT_bar",
+	"object": "event",
+	"type": "session.revoked",
+	"created_at": "2020-01-01T01:01:01Z",
+	"data": {
+		"id": "SES_foo",
+		"object": "session",
+		"status": "revoked",
+		"user_id": "USR_foo",
+		"created_at": "2020-01-01T01:01:01Z",
+		"revoked_at": "2020-01-01T01:01:01Z"
+	}
+}`)
+
+func TestConstructEvent(t *testing.T) {
+	signature := webhook.ComputeSignature(sampleUserCreatedPayload, sampleSecret, time.Now())
+	event, err := webhook.ConstructEvent(sampleUserCreatedPayload, signature, sampleSecret)
+	assert.Nil(t, err)
+	assert.Equal(t, "EVT_foo", event.ID)
+	assert.Equal(t, webhook.EventType(webhook.EventTypeUserCreated), event.Type)
+	assert.Equal(t, "USR_foo", event.User.ID)
+	assert.Equal(t, "foo@bar.com", *event.User.Email)
+	assert.Nil(t, event.Session)
+	assert.Nil(t, event.Credential)
+}
+
+func TestConstructEvent_Session(t *testing.T) {
+	signature := webhook.ComputeSignature(sampleSessionRevokedPayload, sampleSecret, time.Now())
+	event, err := webhook.ConstructEvent(sampleSessionRevokedPayload, signature, sampleSecret)
+	assert.Nil(t, err)
+	assert.Equal(t, "SES_foo", event.Session.ID)
+	assert.Equal(t, feather.SessionStatus(feather.SessionStatusRevoked), event.Session.Status)
+	assert.Nil(t, event.User)
+}
+
+func TestConstructEvent_MultipleSignatures(t *testing.T) {
+	signature := webhook.ComputeSignature(sampleUserCreatedPayload, sampleSecret, time.Now())
+	signature = strings.Replace(signature, ",", ",v1=deadbeef,", 1)
+	event, err := webhook.ConstructEvent(sampleUserCreatedPayload, signature, sampleSecret)
+	assert.Nil(t, err)
+	assert.Equal(t, "EVT_foo", event.ID)
+}
+
+func TestConstructEvent_InvalidSignature(t *testing.T) {
+	for _, signature := range []string{
+		"",
+		"foo",
+		"t=foo,v1=bar",
+		webhook.ComputeSignature(sampleUserCreatedPayload, "whsec_bar", time.Now()),
+		webhook.ComputeSignature([]byte("{}"), sampleSecret, time.Now()),
+	} {
+		event, err := webhook.ConstructEvent(sampleUserCreatedPayload, signature, sampleSecret)
+		assert.Nil(t, event)
+		assert.Equal(t, feather.ErrorCodeWebhookSignatureInvalid, err.(feather.Error).Code)
+	}
+}
+
+func TestConstructEvent_Replay(t *testing.T) {
+	signature := webhook.ComputeSignature(sampleUserCreatedPayload, sampleSecret, time.Now().Add(-10*time.Minute))
+	event, err := webhook.ConstructEvent(sampleUserCreatedPayload, signature, sampleSecret)
+	assert.Nil(t, event)
+	assert.Equal(t, feather.ErrorCodeWebhookTimestampExpired, err.(feather.Error).Code)
+
+	event, err = webhook.ConstructEventWithTolerance(sampleUserCreatedPayload, signature, sampleSecret, time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, "EVT_foo", event.ID)
+
+	// Replay protection cannot be disabled
+	for _, tolerance := range []time.Duration{0, -time.Hour} {
+		event, err = webhook.ConstructEventWithTolerance(sampleUserCreatedPayload, signature, sampleSecret, tolerance)
+		assert.Nil(t, event)
+		assert.Equal(t, feather.ErrorCodeParameterInvalid, err.(feather.Error).Code)
+	}
+}
+
+func TestConstructEvent_Unparsable(t *testing.T) {
+	payload := []byte(`{"type": "user.created", "data": "foo"}`)
+	signature := webhook.ComputeSignature(payload, sampleSecret, time.Now())
+	event, err := webhook.ConstructEvent(payload, signature, sampleSecret)
+	assert.Nil(t, event)
+	assert.Equal(t, feather.ErrorCodeParsingFailed, err.(feather.Error).Code)
+}
+
+func TestHandler(t *testing.T) {
+	var received []*webhook.Event
+	handler := webhook.NewHandler(sampleSecret)
+	handler.Handle(webhook.EventTypeUserCreated, func(event *webhook.Event) error {
+		received = append(received, event)
+		return nil
+	})
+	handler.Handle(webhook.EventTypeSessionRevoked, func(event *webhook.Event) error {
+		return errors.New("foo")
+	})
+
+	send := func(payload []byte, signature string) int {
+		r := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(string(payload)))
+		r.Header.Set(webhook.SignatureHeader, signature)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// Registered event type
+	code := send(sampleUserCreatedPayload, webhook.ComputeSignature(sampleUserCreatedPayload, sampleSecret, time.Now()))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, len(received))
+	assert.Equal(t, "USR_foo", received[0].User.ID)
+
+	// Handler error
+	code = send(sampleSessionRevokedPayload, webhook.ComputeSignature(sampleSessionRevokedPayload, sampleSecret, time.Now()))
+	assert.Equal(t, http.StatusInternalServerError, code)
+
+	// Unregistered event type
+	payload := []byte(`{"id": "EVT_baz", "object": "event", "type": "credential.created", "data": {"id": "CRD_foo"}}`)
+	code = send(payload, webhook.ComputeSignature(payload, sampleSecret, time.Now()))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, len(received))
+
+	// Invalid signature
+	code = send(sampleUserCreatedPayload, "t=0,v1=foo")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, 1, len(received))
+}
+
+func TestHandler_MethodNotAllowed(t *testing.T) {
+	handler := webhook.NewHandler(sampleSecret)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestHandler_ZeroTolerance(t *testing.T) {
+	handler := webhook.NewHandler(sampleSecret)
+	handler.Tolerance = 0
+	send := func(at time.Time) int {
+		r := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(string(sampleUserCreatedPayload)))
+		r.Header.Set(webhook.SignatureHeader, webhook.ComputeSignature(sampleUserCreatedPayload, sampleSecret, at))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, send(time.Now()))
+	assert.Equal(t, http.StatusBadRequest, send(time.Now().Add(-time.Hour)))
+}
+
+func TestHandler_ZeroValue(t *testing.T) {
+	var handler webhook.Handler
+	assert.NotPanics(t, func() {
+		handler.Handle(webhook.EventTypeUserCreated, func(event *webhook.Event) error {
+			t.Error("handler should not be called")
+			return nil
+		})
+	})
+
+	// Without a secret every request is rejected, even one signed with an empty secret
+	r := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(string(sampleUserCreatedPayload)))
+	r.Header.Set(webhook.SignatureHeader, webhook.ComputeSignature(sampleUserCreatedPayload, "", time.Now()))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}